@@ -0,0 +1,62 @@
+package cosmo
+
+import (
+	"math"
+)
+
+// arcsecInARadian is 180/pi * 3600
+const arcsecInARadian = 180 / math.Pi * 3600 // arcsec/rad
+
+// KpcProperPerArcsec is the proper transverse distance subtended by one arcsecond at z.
+func KpcProperPerArcsec(cos FLRW, z float64) (distanceKpcArcsec float64) {
+	return 1000 * cos.AngularDiameterDistance(z) / arcsecInARadian
+}
+
+// KpcComovingPerArcsec is the comoving transverse distance subtended by one arcsecond at z.
+func KpcComovingPerArcsec(cos FLRW, z float64) (distanceKpcArcsec float64) {
+	return 1000 * cos.ComovingTransverseDistance(z) / arcsecInARadian
+}
+
+// ArcsecPerKpcProper is the angle subtended by one proper kpc at z.
+func ArcsecPerKpcProper(cos FLRW, z float64) (angleArcsecKpc float64) {
+	return arcsecInARadian / (1000 * cos.AngularDiameterDistance(z))
+}
+
+// ArcsecPerKpcComoving is the angle subtended by one comoving kpc at z.
+func ArcsecPerKpcComoving(cos FLRW, z float64) (angleArcsecKpc float64) {
+	return arcsecInARadian / (1000 * cos.ComovingTransverseDistance(z))
+}
+
+// ComovingSeparation is the comoving distance between two objects at z1 and z2
+// separated by thetaArcsec on the sky.
+//
+// With comoving distances X1, X2 along the line of sight, and R = D_H / sqrt(|Ok0|),
+// this is the law of cosines in flat, hyperbolic, or spherical space,
+// written in half-angle form to keep its precision for close pairs:
+//   (d/2)^2        = ((X1-X2)/2)^2        + X1 X2 sin^2(theta/2)              Ok0 = 0
+//   sinh^2(d/2R)   = sinh^2((X1-X2)/2R)   + sinh(X1/R) sinh(X2/R) sin^2(theta/2)  Ok0 > 0
+//   sin^2(d/2R)    = sin^2((X1-X2)/2R)    + sin(X1/R) sin(X2/R) sin^2(theta/2)    Ok0 < 0
+//
+// For z1 = z2 and small angles, d is KpcComovingPerArcsec(cos, z) * thetaArcsec / 1000.
+// There is no unique proper separation between objects at different redshifts;
+// for a pair at the same redshift it is d / (1+z).
+func ComovingSeparation(cos FLRW, z1, z2, thetaArcsec float64) (distanceMpc float64) {
+	x1 := cos.ComovingDistance(z1)
+	x2 := cos.ComovingDistance(z2)
+	sinHalfTheta := math.Sin(thetaArcsec / arcsecInARadian / 2)
+	s2 := sinHalfTheta * sinHalfTheta
+	Ok0 := cos.Ok0()
+
+	switch {
+	case Ok0 == 0:
+		return 2 * math.Sqrt((x1-x2)*(x1-x2)/4+x1*x2*s2)
+	case Ok0 > 0:
+		R := cos.HubbleDistance() / math.Sqrt(Ok0)
+		sh := math.Sinh((x1 - x2) / (2 * R))
+		return 2 * R * math.Asinh(math.Sqrt(sh*sh+math.Sinh(x1/R)*math.Sinh(x2/R)*s2))
+	default:
+		R := cos.HubbleDistance() / math.Sqrt(-Ok0)
+		sn := math.Sin((x1 - x2) / (2 * R))
+		return 2 * R * math.Asin(math.Sqrt(sn*sn+math.Sin(x1/R)*math.Sin(x2/R)*s2))
+	}
+}
@@ -0,0 +1,63 @@
+package cosmo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKpcPerArcsec(t *testing.T) {
+	for _, cos := range []FLRW{
+		FlatLCDM{H0: 70, Om0: 0.3},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.5},
+	} {
+		for _, z := range []float64{0.1, 1, 3} {
+			// 1 arcsec = pi / 648000 rad
+			exp := 1000 * cos.AngularDiameterDistance(z) * math.Pi / 648000
+			runTest(func(z float64) float64 { return KpcProperPerArcsec(cos, z) }, z, exp, 1e-12, t, 0)
+			runTest(func(z float64) float64 { return KpcComovingPerArcsec(cos, z) }, z, (1+z)*exp, 1e-12, t, 0)
+			runTest(func(z float64) float64 { return ArcsecPerKpcProper(cos, z) * KpcProperPerArcsec(cos, z) }, z, 1, 1e-12, t, 0)
+			runTest(func(z float64) float64 { return ArcsecPerKpcComoving(cos, z) * KpcComovingPerArcsec(cos, z) }, z, 1, 1e-12, t, 0)
+		}
+	}
+}
+
+// lawOfCosines is the direct form of the comoving separation,
+// which is precise for large separations.
+func lawOfCosines(cos FLRW, z1, z2, theta float64) float64 {
+	x1, x2 := cos.ComovingDistance(z1), cos.ComovingDistance(z2)
+	Ok0 := cos.Ok0()
+	switch {
+	case Ok0 == 0:
+		return math.Sqrt(x1*x1 + x2*x2 - 2*x1*x2*math.Cos(theta))
+	case Ok0 > 0:
+		R := cos.HubbleDistance() / math.Sqrt(Ok0)
+		return R * math.Acosh(math.Cosh(x1/R)*math.Cosh(x2/R)-math.Sinh(x1/R)*math.Sinh(x2/R)*math.Cos(theta))
+	}
+	R := cos.HubbleDistance() / math.Sqrt(-Ok0)
+	return R * math.Acos(math.Cos(x1/R)*math.Cos(x2/R)+math.Sin(x1/R)*math.Sin(x2/R)*math.Cos(theta))
+}
+
+func TestComovingSeparation(t *testing.T) {
+	for _, cos := range []FLRW{
+		FlatLCDM{H0: 70, Om0: 0.3},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.5},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9},
+	} {
+		z1, z2 := 0.5, 1.5
+		x1, x2 := cos.ComovingDistance(z1), cos.ComovingDistance(z2)
+		sep := func(theta float64) float64 { return ComovingSeparation(cos, z1, z2, theta) }
+		// Along the line of sight, and in opposite directions.
+		runTest(sep, 0, x2-x1, distTol, t, 0)
+		runTest(sep, 648000, x1+x2, distTol, t, 0)
+		for _, thetaDeg := range []float64{10, 60, 120} {
+			exp := lawOfCosines(cos, z1, z2, thetaDeg*math.Pi/180)
+			runTest(sep, thetaDeg*3600, exp, distTol, t, 0)
+		}
+
+		// Close pairs at the same redshift.
+		for _, thetaArcsec := range []float64{1e-3, 1, 60} {
+			exp := KpcComovingPerArcsec(cos, z1) * thetaArcsec / 1000
+			runTest(func(theta float64) float64 { return ComovingSeparation(cos, z1, z1, theta) }, thetaArcsec, exp, 1e-8*exp, t, 0)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cosmo
+
+import (
+	"math"
+)
+
+// BaryonFLRW is a DensityFLRW that also knows its baryon density and CMB temperature,
+// which set the sound horizon of the baryon-photon fluid.
+type BaryonFLRW interface {
+	DensityFLRW
+	Ob(z float64) (baryonDensity float64)
+	Tcmb(z float64) (temperatureK float64)
+}
+
+// neutrinoMassDensityEv is the sum of neutrino masses that gives Onu h^2 = 1
+// for non-relativistic neutrinos.  [eV]
+const neutrinoMassDensityEv = 93.14
+
+// SoundHorizon is the comoving sound horizon at z,
+// the distance traveled by sound in the baryon-photon fluid since the big bang.
+//   r_s(z) = Integral_z^inf c_s(z') / H(z') dz'
+//   c_s = c / sqrt(3 (1 + R)),  R = 3 rho_b / (4 rho_gamma) = 3 Ob0 / (4 Ogamma0 (1+z))
+// It is NaN without radiation, Tcmb0 = 0.
+func SoundHorizon(cos BaryonFLRW, z float64) (distanceMpc float64) {
+	Ogamma0 := cos.Ogamma0()
+	if Ogamma0 == 0 {
+		return math.NaN()
+	}
+	rb := 3 * cos.Ob(0) / (4 * Ogamma0)
+	integrand := func(z float64) float64 {
+		R := rb / (1 + z)
+		return 1 / (math.Sqrt(3*(1+R)) * cos.E(z))
+	}
+	return cos.HubbleDistance() * integrationOf(cos).integrate(integrand, z, math.Inf(1))
+}
+
+// ZDrag is the redshift of the baryon drag epoch, when the baryons are released from the photons,
+// from the fitting formula of
+//   Eisenstein & Hu, 1998, ApJ, 496, 605.  Eq. 4
+// It is ~ 1020 for a Planck cosmology, about 40 lower than from a Boltzmann code,
+// so SoundHorizon(cos, ZDrag(cos)) is ~ 2% larger than SoundHorizonDrag(cos).
+// The matter density includes the massive neutrinos that are non-relativistic today,
+// as the total matter density of Eq. 4 and as for ZStar.
+func ZDrag(cos BaryonFLRW) (z float64) {
+	h := cos.H(0) / 100
+	omhh := (cos.Om(0) + massiveNeutrinoDensity(cos)) * h * h
+	obhh := cos.Ob(0) * h * h
+	b1 := 0.313 * math.Pow(omhh, -0.419) * (1 + 0.607*math.Pow(omhh, 0.674))
+	b2 := 0.238 * math.Pow(omhh, 0.223)
+	return 1291 * math.Pow(omhh, 0.251) / (1 + 0.659*math.Pow(omhh, 0.828)) *
+		(1 + b1*math.Pow(obhh, b2))
+}
+
+// SoundHorizonDrag is the comoving sound horizon at the baryon drag epoch, r_d,
+// from the fitting formula of
+//   Aubourg et al., 2015, PRD, 92, 123516.  Eq. 16
+//   r_d = 55.154 exp(-72.3 (Onu h^2 + 0.0006)^2) / ((Om0 h^2)^0.25351 (Ob0 h^2)^0.12807)  Mpc
+// which matches CAMB to 0.02% for Neff = 3.046 and sum(m_nu) < 0.6 eV.
+// Onu h^2 is the density of the neutrinos that are non-relativistic today, sum(m_nu) / 93.14 eV.
+func SoundHorizonDrag(cos BaryonFLRW) (distanceMpc float64) {
+	h := cos.H(0) / 100
+	omhh := cos.Om(0) * h * h
+	obhh := cos.Ob(0) * h * h
+	onuhh := massiveNeutrinoDensity(cos) * h * h
+	return 55.154 * math.Exp(-72.3*(onuhh+0.0006)*(onuhh+0.0006)) /
+		(math.Pow(omhh, 0.25351) * math.Pow(obhh, 0.12807))
+}
+
+// massiveNeutrinoDensity is the part of Onu0 beyond that of the same neutrinos if they were massless.
+// All neutrinos are relativistic at z = 1e8, where their density relative to the photons is that of
+// massless neutrinos.
+func massiveNeutrinoDensity(cos DensityFLRW) (neutrinoDensity float64) {
+	Ogamma0 := cos.Ogamma0()
+	if Ogamma0 == 0 {
+		return 0
+	}
+	const zRel = 1e8
+	return cos.Onu0() - Ogamma0*cos.Onu(zRel)/cos.Ogamma(zRel)
+}
+
+// HubbleDistanceAt is the Hubble distance at z, D_H(z) = c / H(z).
+func HubbleDistanceAt(cos FLRW, z float64) (distanceMpc float64) {
+	return cos.HubbleDistance() * cos.Einv(z)
+}
+
+// VolumeAveragedDistance is the spherically averaged BAO distance
+//   D_V(z) = (z D_M(z)^2 D_H(z))^(1/3)
+//   Eisenstein et al., 2005, ApJ, 633, 560.  Eq. 2
+func VolumeAveragedDistance(cos FLRW, z float64) (distanceMpc float64) {
+	dM := cos.ComovingTransverseDistance(z)
+	return math.Cbrt(z * dM * dM * HubbleDistanceAt(cos, z))
+}
+
+// DMOverRd is the transverse BAO observable D_M(z) / r_d
+// with r_d from SoundHorizonDrag.
+func DMOverRd(cos BaryonFLRW, z float64) (ratio float64) {
+	return cos.ComovingTransverseDistance(z) / SoundHorizonDrag(cos)
+}
+
+// DHOverRd is the line-of-sight BAO observable D_H(z) / r_d
+// with r_d from SoundHorizonDrag.
+func DHOverRd(cos BaryonFLRW, z float64) (ratio float64) {
+	return HubbleDistanceAt(cos, z) / SoundHorizonDrag(cos)
+}
+
+// DVOverRd is the isotropic BAO observable D_V(z) / r_d
+// with r_d from SoundHorizonDrag.
+func DVOverRd(cos BaryonFLRW, z float64) (ratio float64) {
+	return VolumeAveragedDistance(cos, z) / SoundHorizonDrag(cos)
+}
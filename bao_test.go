@@ -0,0 +1,64 @@
+package cosmo
+
+import (
+	"math"
+	"testing"
+)
+
+// planck18 is the Planck 2018 TT,TE,EE+lowE+lensing cosmology,
+// Omega_b h^2 = 0.02237, Omega_c h^2 = 0.1200, H0 = 67.36, one massive neutrino of 0.06 eV.
+// CAMB gives z_drag = 1059.94 and r_drag = 147.09 Mpc.
+//   Planck Collaboration, 2020, A&A, 641, A6.  Table 2
+var planck18 = FlatLCDM{H0: 67.36, Om0: 0.14237 / (0.6736 * 0.6736), Ob0: 0.02237 / (0.6736 * 0.6736),
+	Tcmb0: 2.7255, Neff: 3.046, MNu: []float64{0.06}}
+
+func TestSoundHorizon(t *testing.T) {
+	rd := 147.09
+	runTest(func(z float64) float64 { return SoundHorizon(planck18, z) }, 1059.94, rd, 5e-4*rd, t, 0)
+	runTest(func(float64) float64 { return SoundHorizonDrag(planck18) }, 0, rd, 5e-4*rd, t, 0)
+
+	// The Eisenstein & Hu z_d is ~ 40 lower than from CAMB.
+	runTest(func(float64) float64 { return ZDrag(planck18) }, 0, 1020, 5, t, 0)
+	// Both ZDrag and ZStar use the total matter density, including the massive neutrino.
+	withNu := planck18
+	withNu.Om0 += massiveNeutrinoDensity(planck18)
+	withNu.MNu = nil
+	runTest(func(float64) float64 { return ZDrag(planck18) }, 0, ZDrag(withNu), 1e-9, t, 0)
+	runTest(func(float64) float64 { return ZStar(planck18) }, 0, ZStar(withNu), 1e-9, t, 0)
+
+	h := 0.6736
+	exp := 0.06 / neutrinoMassDensityEv / (h * h)
+	runTest(func(float64) float64 { return massiveNeutrinoDensity(planck18) }, 0, exp, 0.01*exp, t, 0)
+	runTest(func(float64) float64 {
+		return massiveNeutrinoDensity(FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.7255, Neff: 3.046})
+	}, 0, 0, 1e-12, t, 0)
+
+	if r := SoundHorizon(FlatLCDM{H0: 70, Om0: 0.3, Ob0: 0.05}, 1000); !math.IsNaN(r) {
+		t.Errorf("Expected NaN sound horizon without radiation, got %v", r)
+	}
+}
+
+// In an Einstein-de Sitter universe
+//   D_M = 2 D_H (1 - 1/sqrt(1+z))
+//   D_H(z) = D_H (1+z)^(-3/2)
+func TestVolumeAveragedDistance(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 1}
+	dH := hubbleDistance(70)
+	for _, z := range []float64{0.15, 0.5, 1.0, 2.3} {
+		dM := 2 * dH * (1 - 1/math.Sqrt(1+z))
+		dHz := dH * math.Pow(1+z, -1.5)
+		runTest(func(z float64) float64 { return HubbleDistanceAt(cos, z) }, z, dHz, distTol, t, 0)
+		runTest(func(z float64) float64 { return VolumeAveragedDistance(cos, z) }, z, math.Cbrt(z*dM*dM*dHz), distTol, t, 0)
+	}
+}
+
+func TestBAORatios(t *testing.T) {
+	rd := SoundHorizonDrag(planck18)
+	z := 0.51
+	runTest(func(z float64) float64 { return DMOverRd(planck18, z) }, z, planck18.ComovingTransverseDistance(z)/rd, 1e-12, t, 0)
+	runTest(func(z float64) float64 { return DHOverRd(planck18, z) }, z, HubbleDistanceAt(planck18, z)/rd, 1e-12, t, 0)
+	runTest(func(z float64) float64 { return DVOverRd(planck18, z) }, z, VolumeAveragedDistance(planck18, z)/rd, 1e-12, t, 0)
+	// D_V^3 = z D_M^2 D_H
+	dm, dh, dv := DMOverRd(planck18, z), DHOverRd(planck18, z), DVOverRd(planck18, z)
+	runTest(func(z float64) float64 { return z * dm * dm * dh }, z, dv*dv*dv, 1e-9, t, 0)
+}
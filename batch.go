@@ -0,0 +1,206 @@
+package cosmo
+
+import (
+	"gonum.org/v1/gonum/integrate/quad"
+	"math"
+	"sort"
+)
+
+// The batch functions evaluate an FLRW method for a whole slice of redshifts.
+// They sort the redshifts and integrate 1/E(z) once, cumulatively, from z=0 outwards,
+// with batchOrder-point Gauss-Legendre quadrature on cells of at most batchStep in ln(1+z).
+// Each redshift then costs ~ batchOrder evaluations of E(z)
+// instead of the 1000 of a single call,
+// with the same accuracy as long as 1/E(z) is smooth on the scale of batchStep.
+const (
+	batchOrder = 8    // Gauss-Legendre points per integration cell
+	batchStep  = 0.01 // Maximum width of an integration cell in ln(1+z)
+)
+
+const errBatchLength = "cosmo: len(out) != len(zs)"
+
+// ComovingDistances sets out[i] to cos.ComovingDistance(zs[i]).  [Mpc]
+// zs need not be sorted.  It panics if len(out) != len(zs).
+func ComovingDistances(cos FLRW, zs, out []float64) {
+	integrand := func(x float64) float64 {
+		opz := math.Exp(x)
+		return opz * cos.Einv(opz-1)
+	}
+	cumulativeIntegral(integrand, zs, out)
+	hubbleDistance := cos.HubbleDistance()
+	for i := range out {
+		out[i] *= hubbleDistance
+	}
+	fillNonFinite(cos.ComovingDistance, zs, out)
+}
+
+// ComovingTransverseDistances sets out[i] to cos.ComovingTransverseDistance(zs[i]).  [Mpc]
+// zs need not be sorted.  It panics if len(out) != len(zs).
+func ComovingTransverseDistances(cos FLRW, zs, out []float64) {
+	ComovingDistances(cos, zs, out)
+	for i := range out {
+		out[i] = comovingTransverseDistanceFromComoving(cos, out[i])
+	}
+}
+
+// AngularDiameterDistances sets out[i] to cos.AngularDiameterDistance(zs[i]).  [Mpc]
+// zs need not be sorted.  It panics if len(out) != len(zs).
+func AngularDiameterDistances(cos FLRW, zs, out []float64) {
+	ComovingTransverseDistances(cos, zs, out)
+	for i, z := range zs {
+		out[i] /= 1 + z
+	}
+}
+
+// LuminosityDistances sets out[i] to cos.LuminosityDistance(zs[i]).  [Mpc]
+// zs need not be sorted.  It panics if len(out) != len(zs).
+func LuminosityDistances(cos FLRW, zs, out []float64) {
+	ComovingTransverseDistances(cos, zs, out)
+	for i, z := range zs {
+		out[i] *= 1 + z
+	}
+}
+
+// DistanceModuli sets out[i] to cos.DistanceModulus(zs[i]).  [mag]
+// zs need not be sorted.  It panics if len(out) != len(zs).
+func DistanceModuli(cos FLRW, zs, out []float64) {
+	LuminosityDistances(cos, zs, out)
+	for i := range out {
+		out[i] = 5*math.Log10(out[i]) + 25
+	}
+}
+
+// ComovingVolumes sets out[i] to cos.ComovingVolume(zs[i]).  [Mpc^3]
+// zs need not be sorted.  It panics if len(out) != len(zs).
+func ComovingVolumes(cos FLRW, zs, out []float64) {
+	ComovingDistances(cos, zs, out)
+	for i := range out {
+		out[i] = comovingVolumeFromComoving(cos, out[i])
+	}
+}
+
+// DifferentialComovingVolumes sets out[i] to cos.DifferentialComovingVolume(zs[i]).  [Mpc^3/sr]
+// zs need not be sorted.  It panics if len(out) != len(zs).
+func DifferentialComovingVolumes(cos FLRW, zs, out []float64) {
+	ComovingTransverseDistances(cos, zs, out)
+	hubbleDistance := cos.HubbleDistance()
+	for i, z := range zs {
+		out[i] = hubbleDistance * out[i] * out[i] * cos.Einv(z)
+	}
+}
+
+// LookbackTimes sets out[i] to cos.LookbackTime(zs[i]).  [Gyr]
+// zs need not be sorted.  It panics if len(out) != len(zs).
+func LookbackTimes(cos FLRW, zs, out []float64) {
+	integrand := func(x float64) float64 {
+		return cos.Einv(math.Expm1(x))
+	}
+	cumulativeIntegral(integrand, zs, out)
+	hubbleTime := hubbleTime(SpeedOfLightKmS / cos.HubbleDistance())
+	for i := range out {
+		out[i] *= hubbleTime
+	}
+	fillNonFinite(cos.LookbackTime, zs, out)
+}
+
+// Ages sets out[i] to cos.Age(zs[i]).  [Gyr]
+// zs need not be sorted.  It panics if len(out) != len(zs).
+func Ages(cos FLRW, zs, out []float64) {
+	LookbackTimes(cos, zs, out)
+	age0 := cos.Age(0)
+	for i := range out {
+		out[i] = age0 - out[i]
+	}
+	fillNonFinite(cos.Age, zs, out)
+}
+
+// Es sets out[i] to cos.E(zs[i]).
+// It panics if len(out) != len(zs).
+func Es(cos FLRW, zs, out []float64) {
+	if len(out) != len(zs) {
+		panic(errBatchLength)
+	}
+	for i, z := range zs {
+		out[i] = cos.E(z)
+	}
+}
+
+// Einvs sets out[i] to cos.Einv(zs[i]).
+// It panics if len(out) != len(zs).
+func Einvs(cos FLRW, zs, out []float64) {
+	if len(out) != len(zs) {
+		panic(errBatchLength)
+	}
+	for i, z := range zs {
+		out[i] = cos.Einv(z)
+	}
+}
+
+// cumulativeIntegral sets out[i] to Integral_0^ln(1+zs[i]) f(x) dx.
+// It visits the redshifts in order outwards from z=0, in both directions,
+// and integrates only between neighbouring redshifts.
+// out[i] is NaN for non-finite zs[i] or zs[i] <= -1.
+func cumulativeIntegral(f func(x float64) float64, zs, out []float64) {
+	if len(out) != len(zs) {
+		panic(errBatchLength)
+	}
+	integrate := newCellIntegrator(f)
+
+	order := make([]int, 0, len(zs))
+	for i, z := range zs {
+		if isFinite(z) && (z > -1) {
+			order = append(order, i)
+		} else {
+			out[i] = math.NaN()
+		}
+	}
+	sort.Slice(order, func(a, b int) bool { return zs[order[a]] < zs[order[b]] })
+	nNegative := sort.Search(len(order), func(j int) bool { return zs[order[j]] >= 0 })
+
+	var x, sum float64
+	for _, i := range order[nNegative:] {
+		xi := math.Log1p(zs[i])
+		sum += integrate(x, xi)
+		out[i], x = sum, xi
+	}
+	x, sum = 0, 0
+	for j := nNegative - 1; j >= 0; j-- {
+		i := order[j]
+		xi := math.Log1p(zs[i])
+		sum += integrate(x, xi)
+		out[i], x = sum, xi
+	}
+}
+
+// newCellIntegrator returns the integral of f from a to b, for either a < b or a > b,
+// with batchOrder-point Gauss-Legendre quadrature on cells of at most batchStep.
+func newCellIntegrator(f func(x float64) float64) func(a, b float64) float64 {
+	nodes := make([]float64, batchOrder)
+	weights := make([]float64, batchOrder)
+	quad.Legendre{}.FixedLocations(nodes, weights, -1, 1)
+	return func(a, b float64) float64 {
+		if a == b {
+			return 0
+		}
+		nCells := math.Ceil(math.Abs(b-a) / batchStep)
+		h := (b - a) / nCells
+		var sum float64
+		for j := 0.0; j < nCells; j++ {
+			mid := a + (j+0.5)*h
+			for k, node := range nodes {
+				sum += weights[k] * f(mid+h/2*node)
+			}
+		}
+		return sum * h / 2
+	}
+}
+
+// fillNonFinite sets out[i] to f(zs[i]) for the non-finite zs[i],
+// which the cumulative integration skips.
+func fillNonFinite(f func(float64) float64, zs, out []float64) {
+	for i, z := range zs {
+		if !isFinite(z) {
+			out[i] = f(z)
+		}
+	}
+}
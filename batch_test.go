@@ -0,0 +1,96 @@
+package cosmo
+
+import (
+	"math"
+	"testing"
+)
+
+var batchCosmologies = map[string]FLRW{
+	"FlatLCDM":          FlatLCDM{H0: 70, Om0: 0.3},
+	"FlatLCDMRadiation": FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04},
+	"LambdaCDMOpen":     LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.5},
+	"LambdaCDMClosed":   LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9},
+	"WCDM":              WCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9},
+	"FlatWCDM":          FlatWCDM{H0: 70, Om0: 0.3, W0: -0.9},
+	"WACDM":             WACDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, WA: 0.2},
+	"FlatWACDM":         FlatWACDM{H0: 70, Om0: 0.3, W0: -0.9, WA: 0.2},
+	"WzCDM":             WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: wLinder(-0.9, 0.2)},
+}
+
+// zBatch is unsorted, with a repeated redshift and z=0.
+var zBatch = []float64{2.0, 0.5, 10.0, 0, 0.5, 1.0, 3.0, 0.01, 1100}
+
+func TestBatch(t *testing.T) {
+	for name, cos := range batchCosmologies {
+		for fname, tc := range map[string]struct {
+			batch  func(FLRW, []float64, []float64)
+			single func(float64) float64
+		}{
+			"ComovingDistance":           {ComovingDistances, cos.ComovingDistance},
+			"ComovingTransverseDistance": {ComovingTransverseDistances, cos.ComovingTransverseDistance},
+			"AngularDiameterDistance":    {AngularDiameterDistances, cos.AngularDiameterDistance},
+			"LuminosityDistance":         {LuminosityDistances, cos.LuminosityDistance},
+			"DistanceModulus":            {DistanceModuli, cos.DistanceModulus},
+			"ComovingVolume":             {ComovingVolumes, cos.ComovingVolume},
+			"DifferentialComovingVolume": {DifferentialComovingVolumes, cos.DifferentialComovingVolume},
+			"LookbackTime":               {LookbackTimes, cos.LookbackTime},
+			"Age":                        {Ages, cos.Age},
+			"E":                          {Es, cos.E},
+			"Einv":                       {Einvs, cos.Einv},
+		} {
+			out := make([]float64, len(zBatch))
+			tc.batch(cos, zBatch, out)
+			for i, z := range zBatch {
+				exp := tc.single(z)
+				if out[i] != exp && !(math.Abs(out[i]-exp) <= 1e-9*math.Max(1, math.Abs(exp))) {
+					t.Errorf("%s %ss(%v): expected %v, got %v", name, fname, z, exp, out[i])
+				}
+			}
+		}
+	}
+}
+
+// The analytic age of a flat LCDM universe extends into the future, z < 0.
+func TestBatchFuture(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	zs := []float64{0.5, -0.3, 0, -0.5, -0.1}
+	out := make([]float64, len(zs))
+	Ages(cos, zs, out)
+	for i, z := range zs {
+		runTest(func(float64) float64 { return out[i] }, z, cos.Age(z), 1e-9, t, 0)
+	}
+}
+
+func TestBatchNonFinite(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	zs := []float64{math.NaN(), 1, -2}
+	out := make([]float64, len(zs))
+	ComovingDistances(cos, zs, out)
+	if !math.IsNaN(out[0]) || !math.IsNaN(out[2]) {
+		t.Errorf("Expected NaN for z = NaN and z = -2, got %v and %v", out[0], out[2])
+	}
+	runTest(func(float64) float64 { return out[1] }, 1, cos.ComovingDistance(1), distTol, t, 0)
+}
+
+func TestBatchLengthMismatch(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected a panic for len(out) != len(zs)")
+		}
+	}()
+	ComovingDistances(FlatLCDM{H0: 70, Om0: 0.3}, []float64{1, 2}, make([]float64, 1))
+}
+
+func BenchmarkComovingDistances(b *testing.B) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04}
+	n := 10000
+	zs := make([]float64, n)
+	for j := range zs {
+		zs[j] = 0.001 + 3*float64(j)/float64(n)
+	}
+	out := make([]float64, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ComovingDistances(cos, zs, out)
+	}
+}
@@ -0,0 +1,114 @@
+package cosmo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func benchmarkFlatWACDMEN(n int, b *testing.B) {
+	cos := FlatWACDM{H0: 70, Om0: 0.27, W0: -1.2, WA: 2}
+
+	var z float64
+	zMax := 1.0
+	step := zMax / float64(n)
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < n; j++ {
+			z = 0.001 + step*float64(j)
+			cos.E(z)
+		}
+	}
+}
+
+func BenchmarkFlatWACDMEN(b *testing.B) {
+	benchmarkFlatWACDMEN(10000, b)
+}
+
+func BenchmarkFlatWACDMENdistance(b *testing.B) {
+	benchmarkFlatWACDMNdistance(10000, "E", b)
+}
+
+func BenchmarkFlatWACDME(b *testing.B) {
+	cos := FlatWACDM{H0: 70, Om0: 0.27, W0: -1.2, WA: 2}
+	z := 1.0
+	for i := 0; i < b.N; i++ {
+		cos.E(z)
+	}
+}
+
+func BenchmarkFlatWACDMEinv(b *testing.B) {
+	cos := FlatWACDM{H0: 70, Om0: 0.27, W0: -1.2, WA: 2}
+	z := 1.0
+	for i := 0; i < b.N; i++ {
+		cos.Einv(z)
+	}
+}
+
+// benchmarkFlatWACDMDistance is a helper function to be called by specific benchmarkFlatWACDMs
+func benchmarkFlatWACDMDistance(distFunc string, b *testing.B) {
+	cos := FlatWACDM{H0: 70, Om0: 0.27, W0: -1.2, WA: 2}
+	z := 1.0
+
+	funcToTest := reflect.ValueOf(&cos).MethodByName(distFunc)
+	for i := 0; i < b.N; i++ {
+		funcToTest.Call([]reflect.Value{reflect.ValueOf(z)})
+	}
+}
+
+// benchmarkFlatWACDMDistanceFlatLCDM is a helper function to be called by specific benchmarkFlatWACDMs
+//   for a w=-1 cosmology, which falls back to the FlatLCDM elliptic integral
+func benchmarkFlatWACDMDistanceFlatLCDM(distFunc string, b *testing.B) {
+	cos := FlatWACDM{H0: 70, Om0: 0.27, W0: -1, WA: 0}
+	z := 1.0
+
+	funcToTest := reflect.ValueOf(&cos).MethodByName(distFunc)
+	for i := 0; i < b.N; i++ {
+		funcToTest.Call([]reflect.Value{reflect.ValueOf(z)})
+	}
+}
+
+// benchmarkFlatWACDMNdistance is a helper function to be called by specific benchmarkFlatWACDMs
+func benchmarkFlatWACDMNdistance(n int, distFunc string, b *testing.B) {
+	cos := FlatWACDM{H0: 70, Om0: 0.27, W0: -1.2, WA: 2}
+	funcToTest := reflect.ValueOf(&cos).MethodByName(distFunc)
+	var z float64
+	zMax := 1.0
+	step := zMax / float64(n)
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < n; j++ {
+			z = 0.001 + step*float64(j)
+			funcToTest.Call([]reflect.Value{reflect.ValueOf(z)})
+		}
+	}
+}
+
+func BenchmarkFlatWACDMComovingDistance(b *testing.B) {
+	benchmarkFlatWACDMDistance("ComovingDistance", b)
+}
+
+func BenchmarkFlatWACDMComovingTransverseDistance(b *testing.B) {
+	benchmarkFlatWACDMDistance("ComovingTransverseDistance", b)
+}
+
+func BenchmarkFlatWACDMLuminosityDistance(b *testing.B) {
+	benchmarkFlatWACDMDistance("LuminosityDistance", b)
+}
+
+func BenchmarkFlatWACDMLookbackTime(b *testing.B) {
+	benchmarkFlatWACDMDistance("LookbackTime", b)
+}
+
+func BenchmarkFlatWACDMNComovingDistance(b *testing.B) {
+	benchmarkFlatWACDMNdistance(10000, "ComovingDistance", b)
+}
+
+func BenchmarkFlatWACDMNLuminosityDistance(b *testing.B) {
+	benchmarkFlatWACDMNdistance(10000, "LuminosityDistance", b)
+}
+
+func BenchmarkFlatWACDMNE(b *testing.B) {
+	benchmarkFlatWACDMNdistance(10000, "E", b)
+}
+
+func BenchmarkFlatWACDMComovingDistanceFlatLCDM(b *testing.B) {
+	benchmarkFlatWACDMDistanceFlatLCDM("ComovingDistance", b)
+}
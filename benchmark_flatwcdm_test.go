@@ -0,0 +1,114 @@
+package cosmo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func benchmarkFlatWCDMEN(n int, b *testing.B) {
+	cos := FlatWCDM{H0: 70, Om0: 0.27, W0: -1.2}
+
+	var z float64
+	zMax := 1.0
+	step := zMax / float64(n)
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < n; j++ {
+			z = 0.001 + step*float64(j)
+			cos.E(z)
+		}
+	}
+}
+
+func BenchmarkFlatWCDMEN(b *testing.B) {
+	benchmarkFlatWCDMEN(10000, b)
+}
+
+func BenchmarkFlatWCDMENdistance(b *testing.B) {
+	benchmarkFlatWCDMNdistance(10000, "E", b)
+}
+
+func BenchmarkFlatWCDME(b *testing.B) {
+	cos := FlatWCDM{H0: 70, Om0: 0.27, W0: -1.2}
+	z := 1.0
+	for i := 0; i < b.N; i++ {
+		cos.E(z)
+	}
+}
+
+func BenchmarkFlatWCDMEinv(b *testing.B) {
+	cos := FlatWCDM{H0: 70, Om0: 0.27, W0: -1.2}
+	z := 1.0
+	for i := 0; i < b.N; i++ {
+		cos.Einv(z)
+	}
+}
+
+// benchmarkFlatWCDMDistance is a helper function to be called by specific benchmarkFlatWCDMs
+func benchmarkFlatWCDMDistance(distFunc string, b *testing.B) {
+	cos := FlatWCDM{H0: 70, Om0: 0.27, W0: -1.2}
+	z := 1.0
+
+	funcToTest := reflect.ValueOf(&cos).MethodByName(distFunc)
+	for i := 0; i < b.N; i++ {
+		funcToTest.Call([]reflect.Value{reflect.ValueOf(z)})
+	}
+}
+
+// benchmarkFlatWCDMDistanceFlatLCDM is a helper function to be called by specific benchmarkFlatWCDMs
+//   for a w=-1 cosmology, which falls back to the FlatLCDM elliptic integral
+func benchmarkFlatWCDMDistanceFlatLCDM(distFunc string, b *testing.B) {
+	cos := FlatWCDM{H0: 70, Om0: 0.27, W0: -1}
+	z := 1.0
+
+	funcToTest := reflect.ValueOf(&cos).MethodByName(distFunc)
+	for i := 0; i < b.N; i++ {
+		funcToTest.Call([]reflect.Value{reflect.ValueOf(z)})
+	}
+}
+
+// benchmarkFlatWCDMNdistance is a helper function to be called by specific benchmarkFlatWCDMs
+func benchmarkFlatWCDMNdistance(n int, distFunc string, b *testing.B) {
+	cos := FlatWCDM{H0: 70, Om0: 0.27, W0: -1.2}
+	funcToTest := reflect.ValueOf(&cos).MethodByName(distFunc)
+	var z float64
+	zMax := 1.0
+	step := zMax / float64(n)
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < n; j++ {
+			z = 0.001 + step*float64(j)
+			funcToTest.Call([]reflect.Value{reflect.ValueOf(z)})
+		}
+	}
+}
+
+func BenchmarkFlatWCDMComovingDistance(b *testing.B) {
+	benchmarkFlatWCDMDistance("ComovingDistance", b)
+}
+
+func BenchmarkFlatWCDMComovingTransverseDistance(b *testing.B) {
+	benchmarkFlatWCDMDistance("ComovingTransverseDistance", b)
+}
+
+func BenchmarkFlatWCDMLuminosityDistance(b *testing.B) {
+	benchmarkFlatWCDMDistance("LuminosityDistance", b)
+}
+
+func BenchmarkFlatWCDMLookbackTime(b *testing.B) {
+	benchmarkFlatWCDMDistance("LookbackTime", b)
+}
+
+func BenchmarkFlatWCDMNComovingDistance(b *testing.B) {
+	benchmarkFlatWCDMNdistance(10000, "ComovingDistance", b)
+}
+
+func BenchmarkFlatWCDMNLuminosityDistance(b *testing.B) {
+	benchmarkFlatWCDMNdistance(10000, "LuminosityDistance", b)
+}
+
+func BenchmarkFlatWCDMNE(b *testing.B) {
+	benchmarkFlatWCDMNdistance(10000, "E", b)
+}
+
+func BenchmarkFlatWCDMComovingDistanceFlatLCDM(b *testing.B) {
+	benchmarkFlatWCDMDistanceFlatLCDM("ComovingDistance", b)
+}
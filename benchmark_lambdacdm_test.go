@@ -148,3 +148,10 @@ func BenchmarkLambdaCDMComovingDistanceFlat(b *testing.B) {
 func BenchmarkLambdaCDMComovingDistancePositiveOk0(b *testing.B) {
 	benchmarkLambdaCDMDistancePositiveOk0("ComovingDistance", b)
 }
+
+func BenchmarkLambdaCDMComovingDistanceIntegrate(b *testing.B) {
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9}
+	for i := 0; i < b.N; i++ {
+		cos.comovingDistanceZ1Z2Integrate(0, 1.0)
+	}
+}
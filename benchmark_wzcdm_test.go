@@ -0,0 +1,66 @@
+package cosmo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func BenchmarkWzCDME(b *testing.B) {
+	cos := WzCDM{H0: 70, Om0: 0.2, Ol0: 0.7, W: wLinder(-1.2, 2)}
+	z := 1.0
+	for i := 0; i < b.N; i++ {
+		cos.E(z)
+	}
+}
+
+func BenchmarkWzCDMETable(b *testing.B) {
+	cos, _ := NewWzCDM(70, 0.2, 0.7, wLinder(-1.2, 2))
+	z := 1.0
+	for i := 0; i < b.N; i++ {
+		cos.E(z)
+	}
+}
+
+func BenchmarkWzCDMEDEScale(b *testing.B) {
+	cos := WzCDM{H0: 70, Om0: 0.2, Ol0: 0.7, DEScale: func(z float64) float64 { return 1 + z }}
+	z := 1.0
+	for i := 0; i < b.N; i++ {
+		cos.E(z)
+	}
+}
+
+// benchmarkWzCDMDistance is a helper function to be called by specific benchmarkWzCDMs
+func benchmarkWzCDMDistance(distFunc string, b *testing.B) {
+	cos := WzCDM{H0: 70, Om0: 0.2, Ol0: 0.7, W: wLinder(-1.2, 2)}
+	z := 1.0
+
+	funcToTest := reflect.ValueOf(&cos).MethodByName(distFunc)
+	for i := 0; i < b.N; i++ {
+		funcToTest.Call([]reflect.Value{reflect.ValueOf(z)})
+	}
+}
+
+func BenchmarkWzCDMComovingDistance(b *testing.B) {
+	benchmarkWzCDMDistance("ComovingDistance", b)
+}
+
+func BenchmarkWzCDMLuminosityDistance(b *testing.B) {
+	benchmarkWzCDMDistance("LuminosityDistance", b)
+}
+
+func BenchmarkWzCDMLookbackTime(b *testing.B) {
+	benchmarkWzCDMDistance("LookbackTime", b)
+}
+
+func BenchmarkWzCDMComovingDistanceTable(b *testing.B) {
+	cos, _ := NewWzCDM(70, 0.2, 0.7, wLinder(-1.2, 2))
+	for i := 0; i < b.N; i++ {
+		cos.ComovingDistance(1.0)
+	}
+}
+
+func BenchmarkNewWzCDM(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewWzCDM(70, 0.2, 0.7, wLinder(-1.2, 2))
+	}
+}
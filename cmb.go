@@ -0,0 +1,40 @@
+package cosmo
+
+import (
+	"math"
+)
+
+// ZStar is the redshift of photon decoupling from the fitting formula of
+//   Hu & Sugiyama, 1996, ApJ, 471, 542.  Eq. E1
+// which is accurate to ~ 0.2%, i.e., it is ~ 1092 where a Boltzmann code gives 1090 for Planck.
+// The matter density includes the massive neutrinos that are non-relativistic today,
+// as for ZDrag.
+func ZStar(cos BaryonFLRW) (z float64) {
+	h := cos.H(0) / 100
+	omhh := (cos.Om(0) + massiveNeutrinoDensity(cos)) * h * h
+	obhh := cos.Ob(0) * h * h
+	g1 := 0.0783 * math.Pow(obhh, -0.238) / (1 + 39.5*math.Pow(obhh, 0.763))
+	g2 := 0.560 / (1 + 21.1*math.Pow(obhh, 1.81))
+	return 1048 * (1 + 0.00124*math.Pow(obhh, -0.738)) * (1 + g1*math.Pow(omhh, g2))
+}
+
+// SoundHorizonStar is the comoving sound horizon at photon decoupling, r_s(z*).
+func SoundHorizonStar(cos BaryonFLRW) (distanceMpc float64) {
+	return SoundHorizon(cos, ZStar(cos))
+}
+
+// ShiftParameter is the CMB shift parameter at photon decoupling
+//   R = sqrt(Om0) H0 D_M(z*) / c
+// with the matter density including the massive neutrinos that are non-relativistic today.
+//   Bond, Efstathiou, & Tegmark, 1997, MNRAS, 291, L33
+func ShiftParameter(cos BaryonFLRW) (shiftParameter float64) {
+	Om0 := cos.Om(0) + massiveNeutrinoDensity(cos)
+	return math.Sqrt(Om0) * cos.ComovingTransverseDistance(ZStar(cos)) / cos.HubbleDistance()
+}
+
+// AcousticScale is the multipole of the acoustic scale at photon decoupling
+//   l_A = pi D_M(z*) / r_s(z*)
+func AcousticScale(cos BaryonFLRW) (multipole float64) {
+	zStar := ZStar(cos)
+	return math.Pi * cos.ComovingTransverseDistance(zStar) / SoundHorizon(cos, zStar)
+}
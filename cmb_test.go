@@ -0,0 +1,62 @@
+package cosmo
+
+import (
+	"gonum.org/v1/gonum/integrate/quad"
+	"math"
+	"testing"
+)
+
+// comovingDistanceRefined is the comoving distance integrated in ln(1+z)
+// in 200 pieces of 200-point Gaussian quadrature.
+func comovingDistanceRefined(cos FLRW, z float64) float64 {
+	n, pieces := 200, 200
+	x := math.Log1p(z)
+	integrand := func(x float64) float64 { return math.Exp(x) * cos.Einv(math.Expm1(x)) }
+	var sum float64
+	for i := 0; i < pieces; i++ {
+		a := x * float64(i) / float64(pieces)
+		b := x * float64(i+1) / float64(pieces)
+		sum += quad.Fixed(integrand, a, b, n, nil, 0)
+	}
+	return cos.HubbleDistance() * sum
+}
+
+// TestComovingDistanceDecoupling checks the single 1000-point quadrature
+// of the comoving distance out to z* and beyond with radiation and massive neutrinos.
+func TestComovingDistanceDecoupling(t *testing.T) {
+	for _, cos := range []FLRW{
+		planck18,
+		LambdaCDM{H0: 67.36, Om0: 0.3137, Ol0: 0.68, Tcmb0: 2.7255, Neff: 3.046, MNu: []float64{0.06}},
+		WCDM{H0: 67.36, Om0: 0.3137, Ol0: 0.68, W0: -0.9, Tcmb0: 2.7255, Neff: 3.046},
+		WACDM{H0: 67.36, Om0: 0.3137, Ol0: 0.7, W0: -0.9, WA: 0.1, Tcmb0: 2.7255, Neff: 3.046},
+		FlatWCDM{H0: 67.36, Om0: 0.3137, W0: -0.9, Tcmb0: 2.7255, Neff: 3.046},
+		FlatWACDM{H0: 67.36, Om0: 0.3137, W0: -0.9, WA: 0.1, Tcmb0: 2.7255, Neff: 3.046},
+		WzCDM{H0: 67.36, Om0: 0.3137, Ol0: 0.68, W: wConst(-0.9), Tcmb0: 2.7255, Neff: 3.046},
+	} {
+		for _, z := range []float64{1090, 1e4} {
+			exp := comovingDistanceRefined(cos, z)
+			runTest(cos.ComovingDistance, z, exp, 1e-10*exp, t, 0)
+		}
+	}
+}
+
+// Planck 2018 TT,TE,EE+lowE+lensing, from CAMB:
+//   z* = 1089.92, r_* = 144.43 Mpc, 100 theta_* = 1.04110
+//   Planck Collaboration, 2020, A&A, 641, A6.  Table 2
+// and the distance priors R = 1.7502, l_A = 301.471 at the CAMB z*
+//   Chen, Huang, & Wang, 2019, JCAP, 02, 028.  Table I
+func TestCMBDistancePriors(t *testing.T) {
+	zStar := 1089.92
+	rStar := SoundHorizon(planck18, zStar)
+	runTest(func(float64) float64 { return rStar }, 0, 144.43, 5e-4*144.43, t, 0)
+	theta := 100 * rStar / planck18.ComovingTransverseDistance(zStar)
+	runTest(func(float64) float64 { return theta }, 0, 1.04110, 5e-4, t, 0)
+
+	// The Hu & Sugiyama fit to z* is good to 0.2%,
+	// which moves l_A by about as much, and R by less.
+	runTest(func(float64) float64 { return ZStar(planck18) }, 0, zStar, 3, t, 0)
+	runTest(func(float64) float64 { return ShiftParameter(planck18) }, 0, 1.7502, 0.0046, t, 0)
+	runTest(func(float64) float64 { return AcousticScale(planck18) }, 0, 301.471, 1, t, 0)
+	exp := math.Pi * planck18.ComovingTransverseDistance(ZStar(planck18)) / SoundHorizonStar(planck18)
+	runTest(func(float64) float64 { return AcousticScale(planck18) }, 0, exp, 1e-10, t, 0)
+}
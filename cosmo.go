@@ -13,6 +13,79 @@
 //   LambdaCDM (H0, OM, OL, OK); w = -1
 //   WCDM      (H0, OM, OL, W); w = w0
 //   WACDM     (H0, OM, OL, W0, WA); w = w0 + w_a * (1-a)
+//   FlatWCDM  (H0, OM, W); OL = 1-OM, OK=0; w = w0
+//   FlatWACDM (H0, OM, W0, WA); OL = 1-OM, OK=0; w = w0 + w_a * (1-a)
+//   WzCDM     (H0, OM, OL, W); w = W(z), any user-supplied function
+//   ETable    (H0, OK, table of E(z)); interpolated expansion history
+//
+// Each of these also accepts the CMB temperature today, Tcmb0 [K],
+// and the effective number of neutrino species, Neff,
+// from which the photon and neutrino densities Ogamma0 and Onu0 are derived.
+// Tcmb0 = 0 (the default) means no radiation.
+// Massive neutrinos are specified by the per-species masses MNu [eV];
+// their density moves from radiation-like to matter-like scaling
+// following Komatsu et al., 2011, ApJS, 192, 18.
+// As MNu is a slice, FlatLCDM, LambdaCDM, WCDM, WACDM, FlatWCDM, and FlatWACDM
+// are not comparable with == and cannot be map keys, which they could be before MNu was added.
+// Compare them with reflect.DeepEqual instead.
+//
+// The struct literals are not checked.
+// Use the NewFlatLCDM, NewLambdaCDM, etc. constructors, or call Validate,
+// to get a *ParameterError for unphysical parameters such as H0 <= 0
+// instead of NaN from every method.
+// The constructors take the radiation as an optional Radiation.
+//
+// Non-flat models can have no big bang or can recollapse,
+// in which case E(z) is NaN beyond the turning point.
+// AnalyzeExpansion reports these,
+// and CheckedAge and CheckedComovingDistance return errors for them.
+//
+// ZAtComovingDistance, ZAtLuminosityDistance, ZAtDistanceModulus,
+// and ZAtAngularDiameterDistance invert the distances of any FLRW.
+// ZAtAge and ZAtLookbackTime invert the times.
+//
+// KpcProperPerArcsec, KpcComovingPerArcsec, and their inverses convert angles to
+// transverse distances at z, and ComovingSeparation gives the distance between
+// objects at different redshifts separated by an angle on the sky.
+//
+// SoundHorizon, SoundHorizonDrag, and ZDrag give the sound horizon of the baryon-photon fluid
+// for any BaryonFLRW, and VolumeAveragedDistance, DMOverRd, DHOverRd, and DVOverRd
+// the BAO distance observables.
+// ZStar, SoundHorizonStar, ShiftParameter, and AcousticScale are the CMB distance priors.
+//
+// AngularDiameterDistanceZ1Z2 is the angular diameter distance between two redshifts,
+// from which CriticalSurfaceDensity, TimeDelayDistance, EinsteinRadiusPointMass,
+// and EinsteinRadiusSIS give the basic strong and weak lensing quantities.
+//
+// Distances and times without an analytic form are integrated numerically
+// as set by the Integration field of each type:
+// fixed 1000-point Gauss-Legendre quadrature by default,
+// or adaptive Gauss-Kronrod, tanh-sinh, or Romberg integration to a tolerance.
+// ComovingDistanceWithError, LookbackTimeWithError, and AgeWithError
+// also return an estimate of the integration error.
+//
+// ComovingDistances, LuminosityDistances, LookbackTimes, etc., evaluate an FLRW method
+// for a slice of redshifts at once.  They sort the redshifts and integrate cumulatively,
+// which is much faster than calling the method for each redshift.
+//
+// NewTabulated wraps any FLRW in a Tabulated, which interpolates the comoving distance,
+// look-back time, and age to a guaranteed relative error over a range of redshifts.
+//
+// ConformalTime, ParticleHorizon, EventHorizon, and HubbleSphereRadius give the horizons of any FLRW.
+// The event horizon is finite only for a universe that accelerates forever.
+//
+// Comoving volumes are full sky [Mpc^3] or per steradian [Mpc^3/sr].
+// SurveyVolume and DifferentialSurveyVolume scale them to a survey area in square degrees.
+//
+// All types give the Hubble parameter H(z) and the critical density.
+// All types but ETable also give the density parameters Om(z), Ode(z), Ok(z), Ogamma(z), Onu(z)
+// as a DensityFLRW.  ETable gives Om(z) once its Om0 is set with WithOm0.
+//
+// GrowthFactor, GrowthRate, GrowthIndex, and FSigma8 give the linear growth of structure
+// for any GrowthFLRW, i.e., an FLRW with a matter density Om(z).
+// The baryon density Ob0 is part of Om0 and only matters for the shape of
+// the linear matter power spectrum in the subpackage powspec.
+// The subpackage halo builds halo mass functions on top of it.
 //
 // Equations and numerical formulae based on
 //   Hogg, https://arxiv.org/abs/astro-ph/9905116
@@ -22,6 +95,7 @@
 //   Baes, Camps, Van De Putte, 2017, MNRAS, 468, 927.
 //   Kantowski, 2000, https://arxiv.org/abs/astro-ph/0002334
 //   Thomas and Kantowski, 2000, PRD, 62, 103507.  Eq. 3
+//   Carlson, 1995, Numer. Algorithms, 10, 13.
 //
 // Organizational thoughts based on code in astropy.cosmology
 //   http://docs.astropy.org/en/stable/_modules/astropy/cosmology
@@ -32,14 +106,14 @@
 // depending on the complexity of the cosmology.
 // Analytic cases take ~1µs, while explicit integration is ~200µs.
 //   FlatLCDM      892ns  (analytic for OM<1)
-//   LambdaCDM     139µs
+//   LambdaCDM     ~1µs   (elliptic for Tcmb0=0; 139µs integrated)
 //   WCDM          250µs
 //   WACDM         261µs
 // These numbers are based on the output of `go test -bench ComovingDistance`
 // run on a 2015 MacBook Air: dual-core 2.2 GHz Intel Core i7, 8 GB 1600 MHz DDR3;
 // with Mac OS X 10.13.2 and go 1.9.2
 //
-// 2. The current types FlatLCDM, LambdaCDM, WCDM, WACDM
+// 2. The current types FlatLCDM, LambdaCDM, WCDM, WACDM, FlatWCDM, FlatWACDM, WzCDM
 // implement their methods as value receivers.
 // There's a mild performance hit for using value receivers instead of pointer receivers.
 // This performance penalty is 40% for individual calls to E or Einv
@@ -59,16 +133,24 @@ const SpeedOfLightKmS = 299792.458 // km/s
 const kmInAMpc = 3.08567758149137e19 // km/Mpc
 //   365*24*3600 * one billion
 const secInAGyr = 31557600 * 1e9 // s/Gyr
+// IAU 2015 nominal solar mass, GM_sun / G, as used by astropy.constants
+const massOfSunG = 1.988409870698051e33 // g
+// gCm3ToMsunMpc3 converts a density in g/cm^3 to Msun/Mpc^3
+const gCm3ToMsunMpc3 = (kmInAMpc * 1e5) * (kmInAMpc * 1e5) * (kmInAMpc * 1e5) / massOfSunG
 
 // FLRW specifies the cosmological calculations to be available from
 // Friedmann-Lemaître-Robertson-Walker metrics.
 type FLRW interface {
 	Age(z float64) (timeGyr float64)
 	AngularDiameterDistance(z float64) (distanceMpc float64)
+	AngularDiameterDistanceZ1Z2(z1, z2 float64) (distanceMpc float64)
 	ComovingDistance(z float64) (distanceMpc float64)
 	ComovingDistanceZ1Z2(z1, z2 float64) (distanceMpc float64)
 	ComovingTransverseDistance(z float64) (distanceMpc float64)
 	ComovingTransverseDistanceZ1Z2(z1, z2 float64) (distanceMpc float64)
+	ComovingVolume(z float64) (volumeMpc3 float64)
+	ComovingVolumeZ1Z2(z1, z2 float64) (volumeMpc3 float64)
+	DifferentialComovingVolume(z float64) (volumeMpc3Sr float64)
 	DistanceModulus(z float64) (distanceModulusMag float64)
 	E(z float64) (fractionalHubbleParameter float64)
 	Einv(z float64) (invFractionalHubbleParameter float64)
@@ -77,3 +159,21 @@ type FLRW interface {
 	LuminosityDistance(z float64) (distanceMpc float64)
 	Ok0() (curvatureDensity float64)
 }
+
+// DensityFLRW is an FLRW that also knows the density of each of its components
+// as a fraction of the critical density at z, and the critical density itself.
+// The densities add up to one:  Om + Ode + Ok + Ogamma + Onu = 1
+// ETable has H and the critical density, but does not implement DensityFLRW,
+// as E(z) alone does not say how it splits into dark energy, photons, and neutrinos.
+type DensityFLRW interface {
+	GrowthFLRW
+	Ogamma0() (photonDensity float64)
+	Onu0() (neutrinoDensity float64)
+	Ode(z float64) (darkEnergyDensity float64)
+	Ok(z float64) (curvatureDensity float64)
+	Ogamma(z float64) (photonDensity float64)
+	Onu(z float64) (neutrinoDensity float64)
+	H(z float64) (hubbleParameterKmSMpc float64)
+	CriticalDensity(z float64) (densityGCm3 float64)
+	CriticalDensityMsunMpc3(z float64) (densityMsunMpc3 float64)
+}
@@ -0,0 +1,52 @@
+package cosmo
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDensitySum checks that the density parameters add up to one at all z.
+func TestDensitySum(t *testing.T) {
+	mNu := []float64{0, 0, 0.06}
+	for _, cos := range []DensityFLRW{
+		FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04, MNu: mNu},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.6, Tcmb0: 2.725, Neff: 3.04, MNu: mNu},
+		WCDM{H0: 70, Om0: 0.3, Ol0: 0.6, W0: -0.9, Tcmb0: 2.725, Neff: 3.04, MNu: mNu},
+		WACDM{H0: 70, Om0: 0.3, Ol0: 0.6, W0: -0.9, WA: 0.2, Tcmb0: 2.725, Neff: 3.04, MNu: mNu},
+		FlatWCDM{H0: 70, Om0: 0.3, W0: -0.9, Tcmb0: 2.725, Neff: 3.04, MNu: mNu},
+		FlatWACDM{H0: 70, Om0: 0.3, W0: -0.9, WA: 0.2, Tcmb0: 2.725, Neff: 3.04, MNu: mNu},
+		WzCDM{H0: 70, Om0: 0.3, Ol0: 0.6, W: wLinder(-0.9, 0.2), Tcmb0: 2.725, Neff: 3.04, MNu: mNu},
+	} {
+		sum := func(z float64) float64 {
+			return cos.Om(z) + cos.Ode(z) + cos.Ok(z) + cos.Ogamma(z) + cos.Onu(z)
+		}
+		runTests(sum, []float64{-0.5, 0, 0.5, 1, 10, 1000}, []float64{1, 1, 1, 1, 1, 1}, eTol, t)
+		runTest(cos.Ok, 0, cos.Ok0(), eTol, t, 0)
+		runTest(cos.Ogamma, 0, cos.Ogamma0(), eTol, t, 0)
+		runTest(cos.Onu, 0, cos.Onu0(), eTol, t, 0)
+		runTest(cos.H, 2, 70*cos.E(2), eTol, t, 0)
+	}
+}
+
+// In a flat cosmological constant universe without radiation
+//   Ode(z) = (1 - Om0) / (Om0 (1+z)^3 + 1 - Om0)
+func TestOdeFlatLCDM(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	for _, z := range []float64{0, 0.5, 1, 3} {
+		exp := 0.7 / (0.3*math.Pow(1+z, 3) + 0.7)
+		runTest(cos.Ode, z, exp, eTol, t, 0)
+		runTest(cos.Ogamma, z, 0, eTol, t, 0)
+	}
+}
+
+// Critical density for H0 = 70, 3 H0^2 / (8 pi G) = 9.2038739e-30 g/cm^3
+// with the CODATA 2018 G = 6.67430e-8 cm^3/g/s^2,
+// and the conventional 2.775e11 h^2 Msun/Mpc^3.
+func TestCriticalDensity(t *testing.T) {
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.6}
+	runTest(cos.CriticalDensity, 0, 9.2038739e-30, 1e-37, t, 0)
+	exp := 2.77536627e11 * 0.7 * 0.7
+	runTest(cos.CriticalDensityMsunMpc3, 0, exp, 1e-8*exp, t, 0)
+	exp *= cos.E(1) * cos.E(1)
+	runTest(cos.CriticalDensityMsunMpc3, 1, exp, 1e-8*exp, t, 0)
+}
@@ -0,0 +1,353 @@
+package cosmo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Interpolation selects how ETable interpolates between the tabulated E(z).
+// All schemes interpolate ln E as a function of ln(1+z),
+// in which the expansion history is close to piecewise linear.
+type Interpolation int
+
+const (
+	// InterpCubicSpline is a natural cubic spline.
+	InterpCubicSpline Interpolation = iota
+	// InterpLinear is linear interpolation.
+	InterpLinear
+)
+
+// Extrapolation selects how ETable evaluates E(z) outside the tabulated range.
+type Extrapolation int
+
+const (
+	// ExtrapolateNone returns NaN for E(z) outside the table,
+	// which then propagates to any distance or time that needs it.
+	ExtrapolateNone Extrapolation = iota
+	// ExtrapolatePowerLaw continues E(z) as a power law in (1+z)
+	// with the slope of the first or last tabulated interval.
+	// E.g., a table that reaches well into matter domination
+	// is continued as E ~ (1+z)^(3/2), which is what Age needs.
+	ExtrapolatePowerLaw
+)
+
+// Errors returned by NewETable and ReadETable besides a *ParameterError.
+var (
+	ErrETableLength   = errors.New("cosmo: ETable has the wrong number of points")
+	ErrETableCoverage = errors.New("cosmo: ETable does not cover z=0")
+	ErrETableParse    = errors.New("cosmo: ETable cannot parse the table")
+)
+
+// ETable provides cosmological distances, age, and look-back time
+// for an expansion history given as a table of E(z) = H(z)/H0,
+// e.g., from a Boltzmann code or a reconstruction of H(z) from data.
+//
+// Create an ETable with NewETable or ReadETable.
+// The table must cover z=0, where E must be 1.
+type ETable struct {
+	H0          float64     // Hubble constant at z=0.  [km/s/Mpc]
+	Integration Integration // Numerical integration of distances and times
+	ok0         float64
+	om0         float64
+	z           []float64
+	lnOpz       []float64
+	lnE         []float64
+	interp      interpolator
+	extrap      Extrapolation
+}
+
+// NewETable creates an ETable from tabulated E(z).
+//   H0 : Hubble constant at z=0.  [km/s/Mpc]
+//   Ok0 : Curvature density at z=0.
+//   z : strictly increasing redshifts.  z > -1
+//   E : Hubble parameter as a fraction of its present value at each z.  E > 0
+//   interp : Interpolation scheme.
+//   extrap : Behavior outside the tabulated range.
+//
+// The slices are copied.
+//
+// An error in H0, Ok0, z, E, interp, or extrap is a *ParameterError.
+// An error wrapping ErrETableLength reports too few points or slices of different lengths,
+// and one wrapping ErrETableCoverage a table that does not cover z=0.
+func NewETable(H0, Ok0 float64, z, E []float64, interp Interpolation, extrap Extrapolation) (ETable, error) {
+	const name = "ETable"
+	switch {
+	case len(z) != len(E):
+		return ETable{}, fmt.Errorf("%w: %d redshifts but %d values of E", ErrETableLength, len(z), len(E))
+	case len(z) < 2:
+		return ETable{}, fmt.Errorf("%w: needs at least 2 points, got %d", ErrETableLength, len(z))
+	case (interp == InterpCubicSpline) && (len(z) < 3):
+		return ETable{}, fmt.Errorf("%w: cubic spline needs at least 3 points, got %d", ErrETableLength, len(z))
+	}
+	if err := firstError(validateH0(name, H0), validateFinite(name, []string{"Ok0"}, Ok0)); err != nil {
+		return ETable{}, err
+	}
+
+	cos := ETable{
+		H0:     H0,
+		ok0:    Ok0,
+		om0:    math.NaN(),
+		z:      make([]float64, len(z)),
+		lnOpz:  make([]float64, len(z)),
+		lnE:    make([]float64, len(z)),
+		extrap: extrap,
+	}
+	for i := range z {
+		zName, EName := fmt.Sprintf("z[%d]", i), fmt.Sprintf("E[%d]", i)
+		switch {
+		case !isFinite(z[i]):
+			return ETable{}, &ParameterError{Cosmology: name, Parameter: zName, Value: z[i], Err: ErrNotFinite}
+		case z[i] <= -1:
+			return ETable{}, &ParameterError{Cosmology: name, Parameter: zName, Value: z[i], Err: ErrInconsistent,
+				Detail: "must exceed -1"}
+		case (i > 0) && (z[i] <= z[i-1]):
+			return ETable{}, &ParameterError{Cosmology: name, Parameter: zName, Value: z[i], Err: ErrInconsistent,
+				Detail: fmt.Sprintf("redshifts must be strictly increasing, z[%d] = %v", i-1, z[i-1])}
+		case !isFinite(E[i]):
+			return ETable{}, &ParameterError{Cosmology: name, Parameter: EName, Value: E[i], Err: ErrNotFinite}
+		case E[i] <= 0:
+			return ETable{}, &ParameterError{Cosmology: name, Parameter: EName, Value: E[i], Err: ErrNotPositive}
+		}
+		cos.z[i] = z[i]
+		cos.lnOpz[i] = math.Log1p(z[i])
+		cos.lnE[i] = math.Log(E[i])
+	}
+
+	switch interp {
+	case InterpCubicSpline:
+		cos.interp = newCubicSpline(cos.lnOpz, cos.lnE)
+	case InterpLinear:
+		cos.interp = newLinearInterpolator(cos.lnOpz, cos.lnE)
+	default:
+		return ETable{}, &ParameterError{Cosmology: name, Parameter: "interp", Value: float64(interp),
+			Err: ErrInconsistent, Detail: "unknown Interpolation"}
+	}
+	if (extrap != ExtrapolateNone) && (extrap != ExtrapolatePowerLaw) {
+		return ETable{}, &ParameterError{Cosmology: name, Parameter: "extrap", Value: float64(extrap),
+			Err: ErrInconsistent, Detail: "unknown Extrapolation"}
+	}
+
+	if (z[0] > 0) || (z[len(z)-1] < 0) {
+		return ETable{}, fmt.Errorf("%w: z in [%v, %v]", ErrETableCoverage, z[0], z[len(z)-1])
+	}
+	// Tables are typically rounded, so allow for a bit of slack
+	// in the normalization.
+	const normTol = 1e-4
+	if E0 := cos.E(0); math.Abs(E0-1) > normTol {
+		return ETable{}, &ParameterError{Cosmology: name, Parameter: "E(0)", Value: E0, Err: ErrInconsistent,
+			Detail: "must be 1"}
+	}
+	return cos, nil
+}
+
+// ReadETable reads a table of E(z) and creates an ETable from it.
+// See NewETable for the meaning of the other arguments.
+//
+// Each line holds z and E(z), separated by commas and/or whitespace.
+// Further columns are ignored.
+// Blank lines and lines starting with '#' are skipped.
+// A line that cannot be parsed gives an error wrapping ErrETableParse.
+func ReadETable(r io.Reader, H0, Ok0 float64, interp Interpolation, extrap Extrapolation) (ETable, error) {
+	var z, E []float64
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(fields) < 2 {
+			return ETable{}, fmt.Errorf("%w: line %d: expected z and E, got %q", ErrETableParse, lineNumber, line)
+		}
+		zi, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return ETable{}, fmt.Errorf("%w: line %d: %v", ErrETableParse, lineNumber, err)
+		}
+		Ei, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return ETable{}, fmt.Errorf("%w: line %d: %v", ErrETableParse, lineNumber, err)
+		}
+		z = append(z, zi)
+		E = append(E, Ei)
+	}
+	if err := scanner.Err(); err != nil {
+		return ETable{}, err
+	}
+	return NewETable(H0, Ok0, z, E, interp, extrap)
+}
+
+func (cos ETable) String() string {
+	if len(cos.z) == 0 {
+		return fmt.Sprintf("ETable{H0: %v, Ok0: %v, empty}", cos.H0, cos.ok0)
+	}
+	return fmt.Sprintf("ETable{H0: %v, Ok0: %v, z: [%v, %v], N: %d}",
+		cos.H0, cos.ok0, cos.z[0], cos.z[len(cos.z)-1], len(cos.z))
+}
+
+// Range is the range of tabulated redshifts.
+// It is NaN for an ETable not made by NewETable or ReadETable.
+func (cos ETable) Range() (zMin, zMax float64) {
+	if len(cos.z) == 0 {
+		return math.NaN(), math.NaN()
+	}
+	return cos.z[0], cos.z[len(cos.z)-1]
+}
+
+// Ok0 is the curvature density at z=0
+func (cos ETable) Ok0() (curvatureDensity float64) {
+	return cos.ok0
+}
+
+// integration is the numerical integration of distances and times
+func (cos ETable) integration() Integration {
+	return cos.Integration
+}
+
+// WithOm0 returns a copy of the ETable with the matter density at z=0 set,
+// which the growth of structure needs in addition to E(z).
+func (cos ETable) WithOm0(Om0 float64) ETable {
+	cos.om0 = Om0
+	return cos
+}
+
+// Om is the matter density at z as a fraction of the critical density at z.
+// It is NaN unless the matter density has been set with WithOm0.
+func (cos ETable) Om(z float64) (matterDensity float64) {
+	return cos.om0 * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// H is the Hubble parameter at z.  [km/s/Mpc]
+func (cos ETable) H(z float64) (hubbleParameterKmSMpc float64) {
+	return cos.H0 * cos.E(z)
+}
+
+// CriticalDensity is the critical density at z.  [g/cm^3]
+func (cos ETable) CriticalDensity(z float64) (densityGCm3 float64) {
+	return criticalDensity0(cos.H0) * cos.E(z) * cos.E(z)
+}
+
+// CriticalDensityMsunMpc3 is the critical density at z.  [Msun/Mpc^3]
+func (cos ETable) CriticalDensityMsunMpc3(z float64) (densityMsunMpc3 float64) {
+	return cos.CriticalDensity(z) * gCm3ToMsunMpc3
+}
+
+// DistanceModulus is the magnitude difference between 1 Mpc and
+// the luminosity distance for the given z.
+func (cos ETable) DistanceModulus(z float64) (distanceModulusMag float64) {
+	return 5*math.Log10(cos.LuminosityDistance(z)) + 25
+}
+
+// LuminosityDistance is the radius of effective sphere over which the light has spread out
+func (cos ETable) LuminosityDistance(z float64) (distanceMpc float64) {
+	return (1 + z) * cos.ComovingTransverseDistance(z)
+}
+
+// AngularDiameterDistance is the ratio of physical transverse size to angular size
+func (cos ETable) AngularDiameterDistance(z float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistance(z) / (1 + z)
+}
+
+// AngularDiameterDistanceZ1Z2 is the angular diameter distance of an object at z2
+// as seen by an observer at z1, e.g., D_ls for a lens at z1 and a source at z2.
+func (cos ETable) AngularDiameterDistanceZ1Z2(z1, z2 float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistanceZ1Z2(z1, z2) / (1 + z2)
+}
+
+// ComovingTransverseDistance is the comoving distance at z as seen from z=0
+func (cos ETable) ComovingTransverseDistance(z float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistanceZ1Z2(0, z)
+}
+
+// ComovingTransverseDistanceZ1Z2 is the comoving distance at z2 as seen from z1
+func (cos ETable) ComovingTransverseDistanceZ1Z2(z1, z2 float64) (distanceMpcRad float64) {
+	return comovingTransverseDistanceZ1Z2(cos, z1, z2)
+}
+
+// ComovingVolume is the comoving volume of the whole sky out to z.
+func (cos ETable) ComovingVolume(z float64) (volumeMpc3 float64) {
+	return comovingVolume(cos, z)
+}
+
+// ComovingVolumeZ1Z2 is the comoving volume of the whole sky between z1 and z2.
+func (cos ETable) ComovingVolumeZ1Z2(z1, z2 float64) (volumeMpc3 float64) {
+	return cos.ComovingVolume(z2) - cos.ComovingVolume(z1)
+}
+
+// DifferentialComovingVolume is the comoving volume per unit redshift
+// per steradian at z.
+func (cos ETable) DifferentialComovingVolume(z float64) (volumeMpc3Sr float64) {
+	return differentialComovingVolume(cos, z)
+}
+
+// HubbleDistance is the inverse of the Hubble parameter
+//   distance : [Mpc]
+func (cos ETable) HubbleDistance() float64 {
+	return hubbleDistance(cos.H0)
+}
+
+// ComovingDistance is the distance that is constant with the Hubble flow
+// expressed in the physical distance at z=0.
+func (cos ETable) ComovingDistance(z float64) (distanceMpc float64) {
+	return cos.ComovingDistanceZ1Z2(0, z)
+}
+
+// ComovingDistanceZ1Z2 is the comoving distance between two z
+// using the numerical integration of cos.Integration of the interpolated 1/E(z).
+func (cos ETable) ComovingDistanceZ1Z2(z1, z2 float64) (distanceMpc float64) {
+	return cos.HubbleDistance() * cos.Integration.integrate(cos.Einv, z1, z2)
+}
+
+// LookbackTime is the time from redshift 0 to z.
+func (cos ETable) LookbackTime(z float64) (timeGyr float64) {
+	integrand := func(z float64) float64 { return cos.Einv(z) / (1 + z) }
+	return hubbleTime(cos.H0) * cos.Integration.integrate(integrand, 0, z)
+}
+
+// Age is the time from redshift ∞ to z.
+//
+// This needs E(z) beyond the end of the table,
+// so is NaN unless the table is extrapolated.
+func (cos ETable) Age(z float64) (timeGyr float64) {
+	integrand := func(z float64) float64 {
+		denom := (1 + z) * cos.E(z)
+		return 1 / denom
+	}
+	return hubbleTime(cos.H0) * cos.Integration.integrate(integrand, z, math.Inf(1))
+}
+
+// E is the Hubble parameter as a fraction of its present value,
+// interpolated from the table.
+// It is NaN for an ETable not made by NewETable or ReadETable,
+// and so are all distances and times.
+func (cos ETable) E(z float64) (fractionalHubbleParameter float64) {
+	x := math.Log1p(z)
+	last := len(cos.lnOpz) - 1
+	switch {
+	case last < 1:
+		return math.NaN()
+	case (x >= cos.lnOpz[0]) && (x <= cos.lnOpz[last]):
+		return math.Exp(cos.interp.at(x))
+	case cos.extrap == ExtrapolateNone:
+		return math.NaN()
+	case x < cos.lnOpz[0]:
+		slope := (cos.lnE[1] - cos.lnE[0]) / (cos.lnOpz[1] - cos.lnOpz[0])
+		return math.Exp(cos.lnE[0] + slope*(x-cos.lnOpz[0]))
+	default:
+		slope := (cos.lnE[last] - cos.lnE[last-1]) / (cos.lnOpz[last] - cos.lnOpz[last-1])
+		return math.Exp(cos.lnE[last] + slope*(x-cos.lnOpz[last]))
+	}
+}
+
+// Einv is the inverse Hubble parameter
+func (cos ETable) Einv(z float64) (invFractionalHubbleParameter float64) {
+	return 1 / cos.E(z)
+}
@@ -0,0 +1,187 @@
+package cosmo
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+var zETable = []float64{0.5, 1.0, 2.0, 3.0}
+
+// makeETable tabulates E(z) of 'cos' at n points logarithmically spaced in 1+z
+// from z=0 to z=zMax
+func makeETable(cos FLRW, zMax float64, n int) (z, E []float64) {
+	z = make([]float64, n)
+	E = make([]float64, n)
+	for i := range z {
+		z[i] = math.Expm1(float64(i) / float64(n-1) * math.Log1p(zMax))
+		E[i] = cos.E(z[i])
+	}
+	return z, E
+}
+
+// The expected values are the astropy numbers from the corresponding
+// FlatLCDM, LambdaCDM, and WACDM tables, as the ETables tabulate those cosmologies.
+func TestTableETable(t *testing.T) {
+	tests := []struct {
+		cos      FLRW
+		interp   Interpolation
+		n        int
+		function string
+		exp      []float64
+		tol      float64
+	}{
+		//   FlatLambdaCDM(70, 0.3).luminosity_distance(z)
+		{FlatLCDM{H0: 70, Om0: 0.3}, InterpCubicSpline, 100, "LuminosityDistance", []float64{2832.9380939, 6607.65761177, 15539.58622323, 25422.74174519}, 1e-2},
+		{FlatLCDM{H0: 70, Om0: 0.3}, InterpLinear, 1000, "LuminosityDistance", []float64{2832.9380939, 6607.65761177, 15539.58622323, 25422.74174519}, 1e-1},
+		//   LambdaCDM(70, 0.3, 0.9).comoving_transverse_distance(z)
+		{LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9}, InterpCubicSpline, 100, "ComovingTransverseDistance", []float64{1955.97712629, 3448.46520202, 5299.86707337, 6321.88323979}, 1e-2},
+		//   LambdaCDM(70, 0.3, 0.6).comoving_transverse_distance(z)
+		{LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.6}, InterpCubicSpline, 100, "ComovingTransverseDistance", []float64{1858.34336447, 3239.91725476, 5115.73838737, 6342.43100585}, 1e-2},
+		//   LambdaCDM(70, 0.3, 0.7).lookback_time(z)
+		{FlatLCDM{H0: 70, Om0: 0.3}, InterpCubicSpline, 100, "LookbackTime", []float64{5.04063793, 7.715337, 10.24035689, 11.35445676}, 1e-5},
+		//   w0waCDM(70, 0.3, 0.7, -0.8, 2.5).angular_diameter_distance(z)
+		{WACDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.8, WA: 2.5}, InterpCubicSpline, 100, "AngularDiameterDistance", []float64{1155.52181127, 1393.61319898, 1282.08090454, 1073.63096224}, 1e-2},
+	}
+	for _, test := range tests {
+		z, E := makeETable(test.cos, 5, test.n)
+		cos, err := NewETable(70, test.cos.Ok0(), z, E, test.interp, ExtrapolateNone)
+		if err != nil {
+			t.Fatalf("Failed to create ETable for %v: %v", test.cos, err)
+		}
+		runTestsByName(cos, test.function, zETable, test.exp, test.tol, t)
+	}
+}
+
+// TestETableAgeExtrapolate checks that a table reaching into matter domination
+// gives the correct age when extrapolated as a power law.
+func TestETableAgeExtrapolate(t *testing.T) {
+	z, E := makeETable(FlatLCDM{H0: 70, Om0: 0.3}, 1000, 200)
+	cos, err := NewETable(70, 0, z, E, InterpCubicSpline, ExtrapolatePowerLaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//   FlatLambdaCDM(70, 0.3).age(z)
+	runTests(cos.Age, zETable, []float64{8.42634602, 5.75164694, 3.22662706, 2.11252719}, 1e-4, t)
+
+	noExtrap, err := NewETable(70, 0, z, E, InterpCubicSpline, ExtrapolateNone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if age := noExtrap.Age(1); !math.IsNaN(age) {
+		t.Errorf("Expected NaN age without extrapolation, got %f", age)
+	}
+	if e := noExtrap.E(1001); !math.IsNaN(e) {
+		t.Errorf("Expected NaN E(z) beyond table without extrapolation, got %f", e)
+	}
+}
+
+func TestReadETable(t *testing.T) {
+	table := `# z, E(z) for FlatLCDM{H0: 70, Om0: 1}
+0.0, 1.0
+1.0, 2.8284271247461903
+
+3.0	8.0
+8.0 27.0 extra-column
+`
+	cos, err := ReadETable(strings.NewReader(table), 70, 0, InterpLinear, ExtrapolatePowerLaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Linear in ln E vs ln(1+z) is exact for a power law.
+	//   E = (1+z)^1.5
+	runTests(cos.E, []float64{0.5, 2, 20}, []float64{math.Pow(1.5, 1.5), math.Pow(3, 1.5), math.Pow(21, 1.5)}, eTol, t)
+	//   FlatLambdaCDM(70, 1.0).comoving_distance(z)
+	runTests(cos.ComovingDistance, zETable, []float64{1571.79831586, 2508.77651427, 3620.20576208, 4282.7494}, distTol, t)
+	//   FlatLambdaCDM(70, 1.0).age(z)
+	runTests(cos.Age, zETable, []float64{5.06897781, 3.29239767, 1.79215429, 1.16403836}, ageTol, t)
+
+	zMin, zMax := cos.Range()
+	if zMin != 0 || zMax != 8 {
+		t.Errorf("Expected range [0, 8], got [%f, %f]", zMin, zMax)
+	}
+}
+
+func TestETableErrors(t *testing.T) {
+	tests := map[string]struct {
+		H0     float64
+		z, E   []float64
+		interp Interpolation
+		par    string // Parameter of the *ParameterError, if any
+		err    error
+	}{
+		"LengthMismatch": {70, []float64{0, 1}, []float64{1}, InterpLinear, "", ErrETableLength},
+		"TooShort":       {70, []float64{0}, []float64{1}, InterpLinear, "", ErrETableLength},
+		"SplineTooShort": {70, []float64{0, 1}, []float64{1, 2}, InterpCubicSpline, "", ErrETableLength},
+		"NegativeH0":     {-70, []float64{0, 1, 2}, []float64{1, 2, 3}, InterpLinear, "H0", ErrNotPositive},
+		"NotIncreasing":  {70, []float64{0, 1, 1}, []float64{1, 2, 2}, InterpLinear, "z[2]", ErrInconsistent},
+		"NegativeE":      {70, []float64{0, 1, 2}, []float64{1, -2, 3}, InterpLinear, "E[1]", ErrNotPositive},
+		"NaNE":           {70, []float64{0, 1, 2}, []float64{1, math.NaN(), 3}, InterpLinear, "E[1]", ErrNotFinite},
+		"BadRedshift":    {70, []float64{-1, 0, 1}, []float64{1, 1, 2}, InterpLinear, "z[0]", ErrInconsistent},
+		"NoZero":         {70, []float64{1, 2, 3}, []float64{2, 3, 4}, InterpLinear, "", ErrETableCoverage},
+		"NotNormalized":  {70, []float64{0, 1, 2}, []float64{1.1, 2, 3}, InterpLinear, "E(0)", ErrInconsistent},
+		"UnknownInterp":  {70, []float64{0, 1, 2}, []float64{1, 2, 3}, Interpolation(-1), "interp", ErrInconsistent},
+	}
+	for name, test := range tests {
+		_, err := NewETable(test.H0, 0, test.z, test.E, test.interp, ExtrapolateNone)
+		var perr *ParameterError
+		switch {
+		case !errors.Is(err, test.err):
+			t.Errorf("%s: expected %v, got %v", name, test.err, err)
+		case (test.par != "") && (!errors.As(err, &perr) || (perr.Parameter != test.par)):
+			t.Errorf("%s: expected a *ParameterError for %s, got %v", name, test.par, err)
+		}
+	}
+
+	if _, err := ReadETable(strings.NewReader("0 1\n1 x\n"), 70, 0, InterpLinear, ExtrapolateNone); !errors.Is(err, ErrETableParse) {
+		t.Errorf("Expected ErrETableParse for an unparseable table, got %v", err)
+	}
+}
+
+// A struct literal has no table, and gives NaN rather than panicking.
+func TestETableZeroValue(t *testing.T) {
+	for _, cos := range []ETable{{}, {H0: 70}} {
+		for fname, f := range map[string]func(float64) float64{
+			"E":                cos.E,
+			"ComovingDistance": cos.ComovingDistance,
+			"LookbackTime":     cos.LookbackTime,
+			"Age":              cos.Age,
+		} {
+			if v := f(1); !math.IsNaN(v) {
+				t.Errorf("%v %s(1): expected NaN, got %v", cos, fname, v)
+			}
+		}
+		if zMin, zMax := cos.Range(); !math.IsNaN(zMin) || !math.IsNaN(zMax) {
+			t.Errorf("%v: expected a NaN range, got [%v, %v]", cos, zMin, zMax)
+		}
+		if s := cos.String(); !strings.Contains(s, "empty") {
+			t.Errorf("Expected an empty ETable, got %s", s)
+		}
+	}
+}
+
+func TestETableCosmologyInterface(t *testing.T) {
+	ageDistance := func(cos FLRW) {
+		z := 0.5
+		age := cos.Age(z)
+		dc := cos.ComovingDistance(z)
+		_, _ = age, dc
+	}
+
+	z, E := makeETable(FlatLCDM{H0: 70, Om0: 0.27}, 10, 50)
+	cos, _ := NewETable(70, 0, z, E, InterpCubicSpline, ExtrapolatePowerLaw)
+	ageDistance(cos)
+}
+
+// H and the critical density follow from H0 and the interpolated E(z).
+func TestETableHubbleCriticalDensity(t *testing.T) {
+	exp := FlatLCDM{H0: 70, Om0: 0.3}
+	z, E := makeETable(exp, 5, 100)
+	cos, _ := NewETable(70, 0, z, E, InterpCubicSpline, ExtrapolateNone)
+	for _, z := range zETable {
+		runTest(cos.H, z, exp.H(z), 1e-6*exp.H(z), t, 0)
+		runTest(cos.CriticalDensity, z, exp.CriticalDensity(z), 1e-6*exp.CriticalDensity(z), t, 0)
+		runTest(cos.CriticalDensityMsunMpc3, z, exp.CriticalDensityMsunMpc3(z), 1e-6*exp.CriticalDensityMsunMpc3(z), t, 0)
+	}
+}
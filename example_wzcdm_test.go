@@ -0,0 +1,46 @@
+package cosmo
+
+import (
+	"fmt"
+)
+
+// Calculated via
+//   from astropy.cosmology import w0waCDM
+// for the equivalent w(z) = w0 + wa * z/(1+z)
+//   z = np.asarray([0.5, 1.0, 2.0, 3.0])
+//   w0waCDM(70, 0.3, 0.7, -0.8, 2.5).distmod(z)
+//   w0waCDM(70, 0.3, 0.7, -0.8, 2.5).luminosity_distance(z)
+//   w0waCDM(70, 0.3, 0.7, -0.8, 2.5).angular_diameter_distance(z)
+
+func ExampleWzCDM() {
+	w := func(z float64) float64 { return -0.8 + 2.5*z/(1+z) }
+	cos := WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: w}
+
+	zVec := []float64{0.5, 1.0, 2.0, 3.0}
+	distmodVec := make([]float64, len(zVec))
+	lumdistVec := make([]float64, len(zVec))
+	angdistVec := make([]float64, len(zVec))
+	for i, z := range zVec {
+		distmodVec[i] = cos.DistanceModulus(z)
+		lumdistVec[i] = cos.LuminosityDistance(z)
+		angdistVec[i] = cos.AngularDiameterDistance(z)
+	}
+
+	fmt.Println(cos)
+	fmt.Println("Ok0: ", cos.Ok0())
+	fmt.Println("Distance Modulus [mag]")
+	fmt.Println(distmodVec)
+	fmt.Println("Luminosity Distance [Mpc]")
+	fmt.Println(lumdistVec)
+	fmt.Println("Angular Diameter Distance [Mpc]")
+	fmt.Println(angdistVec)
+	// Output:
+	// WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7}
+	// Ok0:  0
+	// Distance Modulus [mag]
+	// [42.07480332804884 43.731011211176536 45.31078970620773 46.17487505099648]
+	// Luminosity Distance [Mpc]
+	// [2599.92407534829 5574.45279591506 11538.72814084889 17178.095395900764]
+	// Angular Diameter Distance [Mpc]
+	// [1155.5218112659068 1393.613198978765 1282.0809045387657 1073.6309622437977]
+}
@@ -0,0 +1,218 @@
+package cosmo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Errors returned by the checked variants of the FLRW methods.
+var (
+	ErrNoBigBang          = errors.New("cosmo: no big bang")
+	ErrBeyondMaxRedshift  = errors.New("cosmo: redshift beyond the maximum reachable redshift")
+	ErrBeyondRecollapse   = errors.New("cosmo: redshift beyond the recollapse")
+	ErrExpansionUndefined = errors.New("cosmo: expansion history undefined")
+)
+
+// Expansion summarizes where the expansion history of a cosmology is defined,
+// i.e., where E^2(z) = (H(z)/H0)^2 > 0.
+//
+// For non-flat models with a large dark energy density,
+// E^2(z) crosses zero at some z > 0.
+// Going back in time, such a universe contracted to a minimum size
+// and bounced, rather than starting in a big bang,
+// and no light from beyond the bounce redshift ZMax reaches us.
+// Models with negative dark energy or enough positive curvature
+// stop expanding at some future z in (-1, 0) and recollapse.
+type Expansion struct {
+	BigBang     bool    // E(z) is defined back to z=∞
+	ZMax        float64 // Bounce redshift, the maximum reachable redshift.  +Inf if BigBang
+	Recollapse  bool    // Expansion stops in the future
+	ZRecollapse float64 // Redshift of the maximum expansion, -1 < z < 0.  NaN unless Recollapse
+}
+
+func (e Expansion) String() string {
+	return fmt.Sprintf("Expansion{BigBang: %v, ZMax: %v, Recollapse: %v, ZRecollapse: %v}",
+		e.BigBang, e.ZMax, e.Recollapse, e.ZRecollapse)
+}
+
+// Contains reports whether the expansion history is defined at z.
+func (e Expansion) Contains(z float64) bool {
+	if z > e.ZMax {
+		return false
+	}
+	if e.Recollapse && z < e.ZRecollapse {
+		return false
+	}
+	return z > -1
+}
+
+// Range of ln(1+z) scanned by AnalyzeExpansion and the step of the scan.
+// 1+z = 1e10 is well into radiation domination,
+// and 1+z = 1e-10 is far into the dark energy dominated future.
+const (
+	expansionScanLnOpzMax = 23.0
+	expansionScanStep     = 0.005
+)
+
+// AnalyzeExpansion finds the bounce and recollapse redshifts, if any,
+// of the expansion history of cos.
+//
+// E^2(z) is scanned in steps of ln(1+z) from z=0
+// out to 1+z = 1e10 in the past and 1+z = 1e-10 in the future,
+// and the first point at which E(z) is no longer positive is refined by bisection.
+// A model in which E^2(z) only touches zero between two scan points is not detected.
+// An ETable without extrapolation has its maximum reachable redshift
+// at the end of the table.
+//
+// For LambdaCDM and FlatLCDM without radiation,
+// and WCDM and WzCDM with a cosmological constant and no radiation,
+// the turning points are the roots of the cubic E^2(z) in 1+z instead,
+// and no scan is needed.
+func AnalyzeExpansion(cos FLRW) Expansion {
+	if c, ok := cos.(expansionAnalyzer); ok {
+		if e, ok := c.analyzeExpansion(); ok {
+			return e
+		}
+	}
+	return analyzeExpansionScan(cos)
+}
+
+// expansionAnalyzer is implemented by cosmologies whose turning points
+// are known in closed form for some parameters.
+type expansionAnalyzer interface {
+	// analyzeExpansion returns ok=false if there is no closed form for these parameters.
+	analyzeExpansion() (e Expansion, ok bool)
+}
+
+// expansionLambdaMatter is the Expansion for matter, curvature, and a cosmological constant
+// from the real roots x = 1+z of
+//   E^2 = Om0 x^3 + Ok0 x^2 + Ol0
+// Om0 must be positive.
+// E^2(x=1) = 1, so the bounce is at the smallest root above x = 1
+// and the recollapse at the largest root in (0, 1).
+func expansionLambdaMatter(Om0, Ok0, Ol0 float64) Expansion {
+	e := Expansion{BigBang: true, ZMax: math.Inf(1), ZRecollapse: math.NaN()}
+	real3, e1, e2, e3 := cubicRoots(Ok0/Om0, Ol0/Om0)
+	roots := []float64{e1}
+	if real3 {
+		roots = append(roots, e2, e3)
+	}
+	for _, x := range roots {
+		switch {
+		case (x > 1) && (x-1 < e.ZMax):
+			e.BigBang = false
+			e.ZMax = x - 1
+		case (0 < x) && (x < 1) && (!e.Recollapse || (x-1 > e.ZRecollapse)):
+			e.Recollapse = true
+			e.ZRecollapse = x - 1
+		}
+	}
+	return e
+}
+
+// analyzeExpansionScan is AnalyzeExpansion by the scan of E(z).
+func analyzeExpansionScan(cos FLRW) Expansion {
+	e := Expansion{BigBang: true, ZMax: math.Inf(1), ZRecollapse: math.NaN()}
+	if x, ok := firstNonExpanding(cos, expansionScanLnOpzMax); ok {
+		e.BigBang = false
+		e.ZMax = math.Expm1(x)
+	}
+	if x, ok := firstNonExpanding(cos, -expansionScanLnOpzMax); ok {
+		e.Recollapse = true
+		e.ZRecollapse = math.Expm1(x)
+	}
+	return e
+}
+
+// firstNonExpanding scans x = ln(1+z) from 0 towards xEnd
+// for the first point where E(z) is not positive (including NaN).
+// It returns the last x, to within bisection precision, for which E is still positive.
+func firstNonExpanding(cos FLRW, xEnd float64) (x float64, found bool) {
+	expanding := func(x float64) bool { return cos.E(math.Expm1(x)) > 0 }
+
+	step := math.Copysign(expansionScanStep, xEnd)
+	nSteps := int(math.Ceil(xEnd / step))
+	good := 0.0
+	for i := 1; i <= nSteps; i++ {
+		bad := float64(i) * step
+		if expanding(bad) {
+			good = bad
+			continue
+		}
+		// Bisect between the last good and first bad points.
+		const tol = 1e-12
+		for math.Abs(bad-good) > tol {
+			mid := 0.5 * (good + bad)
+			if expanding(mid) {
+				good = mid
+			} else {
+				bad = mid
+			}
+		}
+		return good, true
+	}
+	return 0, false
+}
+
+// CheckedAge is the time from redshift ∞ to z, as cos.Age,
+// but returns an error instead of NaN or a meaningless value
+// if the model has no big bang or z is beyond a recollapse.
+//
+// Each call analyzes the expansion history with AnalyzeExpansion.
+// For many calls, call AnalyzeExpansion once and use its Age method.
+func CheckedAge(cos FLRW, z float64) (timeGyr float64, err error) {
+	return AnalyzeExpansion(cos).Age(cos, z)
+}
+
+// CheckedComovingDistance is the comoving distance to z, as cos.ComovingDistance,
+// but returns an error instead of NaN
+// if z is beyond the bounce redshift or a recollapse.
+//
+// Each call analyzes the expansion history with AnalyzeExpansion.
+// For many calls, call AnalyzeExpansion once and use its ComovingDistance method.
+func CheckedComovingDistance(cos FLRW, z float64) (distanceMpc float64, err error) {
+	return AnalyzeExpansion(cos).ComovingDistance(cos, z)
+}
+
+// Age is CheckedAge for the cosmology cos with the expansion history e,
+// which must be AnalyzeExpansion(cos).
+func (e Expansion) Age(cos FLRW, z float64) (timeGyr float64, err error) {
+	if !e.BigBang {
+		return math.NaN(), fmt.Errorf("%w: %v bounces at z=%v", ErrNoBigBang, cos, e.ZMax)
+	}
+	if err := e.check(cos, z); err != nil {
+		return math.NaN(), err
+	}
+	return finite(cos.Age(z), cos, z)
+}
+
+// ComovingDistance is CheckedComovingDistance for the cosmology cos
+// with the expansion history e, which must be AnalyzeExpansion(cos).
+func (e Expansion) ComovingDistance(cos FLRW, z float64) (distanceMpc float64, err error) {
+	if err := e.check(cos, z); err != nil {
+		return math.NaN(), err
+	}
+	return finite(cos.ComovingDistance(z), cos, z)
+}
+
+// check returns an error if the expansion history is not defined at z.
+func (e Expansion) check(cos FLRW, z float64) error {
+	switch {
+	case z > e.ZMax:
+		return fmt.Errorf("%w: z=%v > %v for %v", ErrBeyondMaxRedshift, z, e.ZMax, cos)
+	case e.Recollapse && (z < e.ZRecollapse):
+		return fmt.Errorf("%w: z=%v < %v for %v", ErrBeyondRecollapse, z, e.ZRecollapse, cos)
+	case !(z > -1):
+		return fmt.Errorf("%w: z=%v for %v", ErrExpansionUndefined, z, cos)
+	}
+	return nil
+}
+
+// finite passes on a result, or an error if the integration still failed.
+func finite(result float64, cos FLRW, z float64) (float64, error) {
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return math.NaN(), fmt.Errorf("%w: %v at z=%v for %v", ErrExpansionUndefined, result, z, cos)
+	}
+	return result, nil
+}
@@ -0,0 +1,183 @@
+package cosmo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+const expansionTol = 1e-9
+
+// E^2 = Om0 (1+z)^3 + Ok0 (1+z)^2 + Ol0 is a cubic in 1+z,
+// so the expected roots are solved directly:
+//   Om0=0.3, Ol0=1.8: 0.3 x^3 - 1.1 x^2 + 1.8 = 0 at x = 1.78629964784689
+//   Om0=0, Ol0=2: 2 - x^2 = 0 at x = sqrt(2)
+//   Om0=2, Ol0=0: 2 x^3 - x^2 = 0 at x = 1/2
+//   Om0=0.3, Ol0=-0.1: 0.3 x^3 + 0.8 x^2 - 0.1 = 0 at x = 1/3
+var testTableExpansion = map[string]struct {
+	cos FLRW
+	exp Expansion
+}{
+	"FlatLCDM": {FlatLCDM{H0: 70, Om0: 0.3},
+		Expansion{BigBang: true, ZMax: math.Inf(1), ZRecollapse: math.NaN()}},
+	"FlatLCDMRadiation": {FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04},
+		Expansion{BigBang: true, ZMax: math.Inf(1), ZRecollapse: math.NaN()}},
+	"LambdaCDMOpen": {LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.5},
+		Expansion{BigBang: true, ZMax: math.Inf(1), ZRecollapse: math.NaN()}},
+	"LambdaCDMBounce": {LambdaCDM{H0: 70, Om0: 0.3, Ol0: 1.8},
+		Expansion{BigBang: false, ZMax: 0.7862996478468904, ZRecollapse: math.NaN()}},
+	"LambdaCDMBounceOL": {LambdaCDM{H0: 70, Om0: 0, Ol0: 2},
+		Expansion{BigBang: false, ZMax: math.Sqrt2 - 1, ZRecollapse: math.NaN()}},
+	"WCDMBounce": {WCDM{H0: 70, Om0: 0.3, Ol0: 1.8, W0: -1},
+		Expansion{BigBang: false, ZMax: 0.7862996478468904, ZRecollapse: math.NaN()}},
+	"LambdaCDMClosedOM": {LambdaCDM{H0: 70, Om0: 2, Ol0: 0},
+		Expansion{BigBang: true, ZMax: math.Inf(1), Recollapse: true, ZRecollapse: -0.5}},
+	"LambdaCDMNegativeOL": {LambdaCDM{H0: 70, Om0: 0.3, Ol0: -0.1},
+		Expansion{BigBang: true, ZMax: math.Inf(1), Recollapse: true, ZRecollapse: -2. / 3}},
+}
+
+func TestTableExpansion(t *testing.T) {
+	for name, test := range testTableExpansion {
+		e := AnalyzeExpansion(test.cos)
+		if e.BigBang != test.exp.BigBang || e.Recollapse != test.exp.Recollapse {
+			t.Errorf("%s: expected %v, got %v", name, test.exp, e)
+			continue
+		}
+		if !sameOrClose(e.ZMax, test.exp.ZMax, expansionTol) {
+			t.Errorf("%s: expected ZMax %v, got %v", name, test.exp.ZMax, e.ZMax)
+		}
+		if !sameOrClose(e.ZRecollapse, test.exp.ZRecollapse, expansionTol) {
+			t.Errorf("%s: expected ZRecollapse %v, got %v", name, test.exp.ZRecollapse, e.ZRecollapse)
+		}
+	}
+}
+
+// The roots of the cubic agree with the scan of E(z).
+func TestAnalyzeExpansionRoots(t *testing.T) {
+	for _, cos := range []FLRW{
+		FlatLCDM{H0: 70, Om0: 0.3},
+		FlatLCDM{H0: 70, Om0: 1.5},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.5},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 1.8},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 3},
+		LambdaCDM{H0: 70, Om0: 2, Ol0: 0},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: -0.1},
+		LambdaCDM{H0: 70, Om0: 3, Ol0: 0.1},
+	} {
+		exp := analyzeExpansionScan(cos)
+		e, ok := cos.(expansionAnalyzer).analyzeExpansion()
+		if !ok {
+			t.Errorf("%v: expected the expansion from the roots", cos)
+			continue
+		}
+		if (e.BigBang != exp.BigBang) || (e.Recollapse != exp.Recollapse) ||
+			!sameOrClose(e.ZMax, exp.ZMax, expansionTol) ||
+			!sameOrClose(e.ZRecollapse, exp.ZRecollapse, expansionTol) {
+			t.Errorf("%v: expected %v, got %v", cos, exp, e)
+		}
+	}
+	if _, ok := (LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.7, Tcmb0: 2.725}).analyzeExpansion(); ok {
+		t.Errorf("Expected no roots with radiation")
+	}
+}
+
+// sameOrClose compares allowing for matching infinities and NaNs.
+func sameOrClose(x, y, tol float64) bool {
+	switch {
+	case math.IsNaN(x) || math.IsNaN(y):
+		return math.IsNaN(x) && math.IsNaN(y)
+	case math.IsInf(x, 0) || math.IsInf(y, 0):
+		return x == y
+	}
+	return math.Abs(x-y) <= tol
+}
+
+func TestCheckedAge(t *testing.T) {
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.7}
+	age, err := CheckedAge(cos, 1)
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	runTest(func(z float64) float64 { return age }, 1, cos.Age(1), ageTol, t, 0)
+
+	_, err = CheckedAge(LambdaCDM{H0: 70, Om0: 0.3, Ol0: 1.8}, 0.5)
+	if !errors.Is(err, ErrNoBigBang) {
+		t.Errorf("Expected ErrNoBigBang, got %v", err)
+	}
+
+	closed := LambdaCDM{H0: 70, Om0: 2, Ol0: 0}
+	if _, err := CheckedAge(closed, -0.4); err != nil {
+		t.Errorf("Unexpected error before the recollapse: %v", err)
+	}
+	if _, err := CheckedAge(closed, -0.6); !errors.Is(err, ErrBeyondRecollapse) {
+		t.Errorf("Expected ErrBeyondRecollapse, got %v", err)
+	}
+}
+
+func TestCheckedComovingDistance(t *testing.T) {
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 1.8}
+	zMax := 0.7862996478468904
+	d, err := CheckedComovingDistance(cos, 0.5)
+	if err != nil {
+		t.Errorf("Unexpected error below the bounce: %v", err)
+	}
+	runTest(func(z float64) float64 { return d }, 0.5, cos.ComovingDistance(0.5), distTol, t, 0)
+
+	if _, err := CheckedComovingDistance(cos, zMax+0.01); !errors.Is(err, ErrBeyondMaxRedshift) {
+		t.Errorf("Expected ErrBeyondMaxRedshift, got %v", err)
+	}
+	if !math.IsNaN(cos.ComovingDistance(zMax + 0.01)) {
+		t.Errorf("Expected the unchecked ComovingDistance beyond the bounce to be NaN")
+	}
+}
+
+// The methods of a precomputed Expansion match the checked functions.
+func TestExpansionMethods(t *testing.T) {
+	for _, cos := range []FLRW{
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 1.8},
+		LambdaCDM{H0: 70, Om0: 2, Ol0: 0},
+		WACDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, WA: 0.2},
+	} {
+		e := AnalyzeExpansion(cos)
+		for _, z := range []float64{-0.6, 0.5, 1, 3} {
+			exp, expErr := CheckedAge(cos, z)
+			obs, err := e.Age(cos, z)
+			if !sameOrClose(obs, exp, 0) || !errors.Is(err, errors.Unwrap(expErr)) {
+				t.Errorf("%v Age(%v): expected %v, %v, got %v, %v", cos, z, exp, expErr, obs, err)
+			}
+			exp, expErr = CheckedComovingDistance(cos, z)
+			obs, err = e.ComovingDistance(cos, z)
+			if !sameOrClose(obs, exp, 0) || !errors.Is(err, errors.Unwrap(expErr)) {
+				t.Errorf("%v ComovingDistance(%v): expected %v, %v, got %v, %v", cos, z, exp, expErr, obs, err)
+			}
+		}
+	}
+}
+
+func BenchmarkCheckedComovingDistance(b *testing.B) {
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.6}
+	for i := 0; i < b.N; i++ {
+		CheckedComovingDistance(cos, 1)
+	}
+}
+
+func BenchmarkCheckedComovingDistanceScan(b *testing.B) {
+	cos := WCDM{H0: 70, Om0: 0.3, Ol0: 0.6, W0: -0.9}
+	for i := 0; i < b.N; i++ {
+		CheckedComovingDistance(cos, 1)
+	}
+}
+
+// TestClosedOM checks the closed matter-only model used above,
+// which previously returned NaN from the open-universe analytic formulae.
+//   E(z) = (1+z) sqrt(1+2z) for Om0=2, Ol0=0
+//   D_C(z=1) = c/H0 * 2 * (atan(sqrt(3)) - atan(1)) = c/H0 * pi/6
+func TestClosedOM(t *testing.T) {
+	cos := LambdaCDM{H0: 70, Om0: 2, Ol0: 0}
+	runTest(cos.ComovingDistance, 1, SpeedOfLightKmS/70*math.Pi/6, distTol, t, 0)
+	for _, f := range []func(float64) float64{cos.Age, cos.LookbackTime} {
+		if v := f(1); math.IsNaN(v) {
+			t.Errorf("Expected a finite time for %v, got %v", cos, v)
+		}
+	}
+}
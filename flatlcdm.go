@@ -2,7 +2,6 @@ package cosmo
 
 import (
 	"fmt"
-	"gonum.org/v1/gonum/integrate/quad"
 	"math"
 )
 
@@ -10,26 +9,136 @@ import (
 // for a flat Universe with a cosmological constant:
 // matter, dark energy, and no curvature,
 // with a w=-1 equation-of-state parameter for dark energy
+//
+// If Tcmb0 is non-zero, the photon and neutrino densities are derived
+// from Tcmb0 and Neff and the dark energy density is reduced accordingly:
+// Ol0 = 1 - Om0 - Ogamma0 - Onu0.
 type FlatLCDM struct {
-	H0      float64 // Hubble constant at z=0.  [km/s/Mpc]
-	Om0     float64 // Matter Density at z=0
-	W0      float64 // Dark energy equation-of-state parameter
-	Ogamma0 float64 // Photon density
-	Onu0    float64 // Neutrino density
+	H0          float64     // Hubble constant at z=0.  [km/s/Mpc]
+	Om0         float64     // Matter Density at z=0
+	Ob0         float64     // Baryon density at z=0, included in Om0
+	W0          float64     // Dark energy equation-of-state parameter
+	Tcmb0       float64     // Temperature of the CMB at z=0.  [K]
+	Neff        float64     // Effective number of neutrino species
+	MNu         []float64   // Masses of the neutrino species.  [eV]
+	Integration Integration // Numerical integration of distances and times
 }
 
-// Tcmb0   float64 // Temperature of the CMB at z=0.  [K]
-// nuToPhotonDensity float64 // Neutrino density / photon density
-
 func (cos FlatLCDM) String() string {
 	return fmt.Sprintf("FlatLCDM{H0: %v, Om0: %v}", cos.H0, cos.Om0)
 }
 
+// NewFlatLCDM creates a FlatLCDM and validates its parameters.
+// The radiation, if any, is given as an optional Radiation, e.g.,
+//   NewFlatLCDM(67.7, 0.31, Radiation{Tcmb0: 2.7255, Neff: 3.046, MNu: []float64{0.06}})
+// and validated with the other parameters.
+func NewFlatLCDM(H0, Om0 float64, rad ...Radiation) (FlatLCDM, error) {
+	r, err := oneRadiation("FlatLCDM", rad)
+	if err != nil {
+		return FlatLCDM{}, err
+	}
+	cos := FlatLCDM{H0: H0, Om0: Om0, Tcmb0: r.Tcmb0, Neff: r.Neff, MNu: r.MNu}
+	if err := cos.Validate(); err != nil {
+		return FlatLCDM{}, err
+	}
+	return cos, nil
+}
+
 // Ok0 is the curvature density at z=0
 func (cos FlatLCDM) Ok0() (curvatureDensity float64) {
 	return 0
 }
 
+// integration is the numerical integration of distances and times
+func (cos FlatLCDM) integration() Integration {
+	return cos.Integration
+}
+
+// Ogamma0 is the photon density at z=0
+func (cos FlatLCDM) Ogamma0() (photonDensity float64) {
+	return ogamma0(cos.H0, cos.Tcmb0)
+}
+
+// Onu0 is the neutrino density at z=0
+func (cos FlatLCDM) Onu0() (neutrinoDensity float64) {
+	return onu0(cos.H0, cos.Tcmb0, cos.Neff, cos.MNu)
+}
+
+// Om is the matter density at z as a fraction of the critical density at z
+func (cos FlatLCDM) Om(z float64) (matterDensity float64) {
+	return cos.Om0 * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Ob is the baryon density at z as a fraction of the critical density at z
+func (cos FlatLCDM) Ob(z float64) (baryonDensity float64) {
+	return cos.Ob0 * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Tcmb is the temperature of the CMB at z.  [K]
+func (cos FlatLCDM) Tcmb(z float64) (temperatureK float64) {
+	return cos.Tcmb0 * (1 + z)
+}
+
+// Ode is the dark energy density at z as a fraction of the critical density at z
+func (cos FlatLCDM) Ode(z float64) (darkEnergyDensity float64) {
+	Ol0 := 1 - (cos.Om0 + cos.Ogamma0() + cos.Onu0())
+	return Ol0 / (cos.E(z) * cos.E(z))
+}
+
+// Ok is the curvature density at z as a fraction of the critical density at z
+func (cos FlatLCDM) Ok(z float64) (curvatureDensity float64) {
+	return cos.Ok0() * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Ogamma is the photon density at z as a fraction of the critical density at z
+func (cos FlatLCDM) Ogamma(z float64) (photonDensity float64) {
+	return cos.Ogamma0() * (1 + z) * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Onu is the neutrino density at z as a fraction of the critical density at z
+func (cos FlatLCDM) Onu(z float64) (neutrinoDensity float64) {
+	return cos.Ogamma0() * nuRelativeDensity(z, cos.Tcmb0, cos.Neff, cos.MNu) *
+		(1 + z) * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// H is the Hubble parameter at z.  [km/s/Mpc]
+func (cos FlatLCDM) H(z float64) (hubbleParameterKmSMpc float64) {
+	return cos.H0 * cos.E(z)
+}
+
+// CriticalDensity is the critical density at z.  [g/cm^3]
+func (cos FlatLCDM) CriticalDensity(z float64) (densityGCm3 float64) {
+	return criticalDensity0(cos.H0) * cos.E(z) * cos.E(z)
+}
+
+// CriticalDensityMsunMpc3 is the critical density at z.  [Msun/Mpc^3]
+func (cos FlatLCDM) CriticalDensityMsunMpc3(z float64) (densityMsunMpc3 float64) {
+	return cos.CriticalDensity(z) * gCm3ToMsunMpc3
+}
+
+// lambdaMatter returns Om0 and Ok0 if the cosmology has only
+// matter, curvature, and a cosmological constant, for which
+// the linear growth factor is given by the Heath integral.
+func (cos FlatLCDM) lambdaMatter() (Om0, Ok0 float64, ok bool) {
+	if cos.Tcmb0 != 0 {
+		return 0, 0, false
+	}
+	return cos.Om0, 0, true
+}
+
+// Validate checks that the parameters describe a physical cosmology.
+// The error, if any, is a *ParameterError for the first problem found.
+func (cos FlatLCDM) Validate() error {
+	const name = "FlatLCDM"
+	return firstError(
+		validateH0(name, cos.H0),
+		validateMatter(name, cos.Om0, cos.Ob0),
+		validateRadiation(name, cos.Tcmb0, cos.Neff, cos.MNu),
+		cos.Integration.validate(name),
+		validateFlat(name, cos.Om0, cos.Ogamma0(), cos.Onu0()),
+	)
+}
+
 // DistanceModulus is the magnitude difference between 1 Mpc and
 // the luminosity distance for the given z.
 func (cos FlatLCDM) DistanceModulus(z float64) (distanceModulusMag float64) {
@@ -46,6 +155,12 @@ func (cos FlatLCDM) AngularDiameterDistance(z float64) (distanceMpcRad float64)
 	return cos.ComovingTransverseDistance(z) / (1 + z)
 }
 
+// AngularDiameterDistanceZ1Z2 is the angular diameter distance of an object at z2
+// as seen by an observer at z1, e.g., D_ls for a lens at z1 and a source at z2.
+func (cos FlatLCDM) AngularDiameterDistanceZ1Z2(z1, z2 float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistanceZ1Z2(z1, z2) / (1 + z2)
+}
+
 // ComovingTransverseDistance is the comoving distance at z as seen from z=0
 func (cos FlatLCDM) ComovingTransverseDistance(z float64) (distanceMpcRad float64) {
 	return cos.ComovingTransverseDistanceZ1Z2(0, z)
@@ -56,6 +171,22 @@ func (cos FlatLCDM) ComovingTransverseDistanceZ1Z2(z1, z2 float64) (distanceMpcR
 	return cos.ComovingDistanceZ1Z2(z1, z2)
 }
 
+// ComovingVolume is the comoving volume of the whole sky out to z.
+func (cos FlatLCDM) ComovingVolume(z float64) (volumeMpc3 float64) {
+	return comovingVolume(cos, z)
+}
+
+// ComovingVolumeZ1Z2 is the comoving volume of the whole sky between z1 and z2.
+func (cos FlatLCDM) ComovingVolumeZ1Z2(z1, z2 float64) (volumeMpc3 float64) {
+	return cos.ComovingVolume(z2) - cos.ComovingVolume(z1)
+}
+
+// DifferentialComovingVolume is the comoving volume per unit redshift
+// per steradian at z.
+func (cos FlatLCDM) DifferentialComovingVolume(z float64) (volumeMpc3Sr float64) {
+	return differentialComovingVolume(cos, z)
+}
+
 // HubbleDistance is the inverse of the Hubble parameter times the speed of light.
 func (cos FlatLCDM) HubbleDistance() (distanceMpc float64) {
 	return hubbleDistance(cos.H0)
@@ -88,19 +219,19 @@ func (cos FlatLCDM) comovingDistanceZ1Z2Elliptic(z1, z2 float64) (distanceMpc fl
 }
 
 // ComovingDistanceZ1Z2Integrate is the comoving distance between two z
-// in a flat lambda CDM cosmology using fixed Gaussian quadrature integration.
+// in a flat lambda CDM cosmology using the numerical integration of cos.Integration.
 func (cos FlatLCDM) comovingDistanceZ1Z2Integrate(z1, z2 float64) (distanceMpc float64) {
-	n := 1000 // Integration will be n-point Gaussian quadrature
-	return cos.HubbleDistance() * quad.Fixed(cos.Einv, z1, z2, n, nil, 0)
+	return cos.HubbleDistance() * cos.Integration.integrate(cos.Einv, z1, z2)
 }
 
 // ComovingDistanceZ1Z2 is the base function for calculation of comoving distances
 //
 // Here is where the choice of fundamental calculation method is made:
 // Elliptic integral, quadrature integration, or analytic for special cases.
+// The elliptic integral only applies in the absence of radiation.
 func (cos FlatLCDM) ComovingDistanceZ1Z2(z1, z2 float64) (distanceMpc float64) {
 	switch {
-	case cos.Om0 < 1:
+	case (cos.Tcmb0 == 0) && (0 < cos.Om0) && (cos.Om0 < 1):
 		return cos.comovingDistanceZ1Z2Elliptic(z1, z2)
 	default:
 		return cos.comovingDistanceZ1Z2Integrate(z1, z2)
@@ -114,13 +245,15 @@ func (cos FlatLCDM) LookbackTime(z float64) (timeGyr float64) {
 
 // LookbackTimeIntegrate is the look-back time using explicit integration
 func (cos FlatLCDM) lookbackTimeIntegrate(z float64) (timeGyr float64) {
-	n := 1000 // Integration will be n-point Gaussian quadrature
 	integrand := func(z float64) float64 { return cos.Einv(z) / (1 + z) }
-	return hubbleTime(cos.H0) * quad.Fixed(integrand, 0, z, n, nil, 0)
+	return hubbleTime(cos.H0) * cos.Integration.integrate(integrand, 0, z)
 }
 
 // Age is the time from redshift ∞ to z
 func (cos FlatLCDM) Age(z float64) (timeGyr float64) {
+	if cos.Tcmb0 != 0 {
+		return cos.ageIntegrate(z)
+	}
 	// Equation is in many sources.  Specifically used
 	// Thomas and Kantowski, 2000, PRD, 62, 103507.
 	if cos.Om0 == 1 {
@@ -130,33 +263,52 @@ func (cos FlatLCDM) Age(z float64) (timeGyr float64) {
 		math.Asinh(math.Sqrt((1/cos.Om0-1)/math.Pow(1+z, 3)))
 }
 
+// zAtAge is the inverse of Age, for the cases in which Age is analytic.
+// ok is false for the other cases.
+func (cos FlatLCDM) zAtAge(timeGyr float64) (z float64, ok bool) {
+	switch {
+	case (cos.Tcmb0 != 0) || !(0 < cos.Om0) || (cos.Om0 > 1):
+		return math.NaN(), false
+	case cos.Om0 == 1:
+		return zAtAgeEdS(timeGyr, cos.H0), true
+	}
+	s := math.Sinh(3. / 2 * math.Sqrt(1-cos.Om0) * timeGyr / hubbleTime(cos.H0))
+	return math.Cbrt((1/cos.Om0-1)/(s*s)) - 1, true
+}
+
 // ageIntegrate is the time from redshift ∞ to z
 // using explicit integration.
 //
-// Age is analytic in a Flat LCDM Universe.
-// This function exists for consistency testing.
+// Age is analytic in a Flat LCDM Universe without radiation.
+// This function exists for consistency testing
+// and for the case of non-zero radiation density.
 // The basic integrand can be found in many texts.
 // I happened to copy this from
 // Thomas and Kantowski, 2000, PRD, 62, 103507.  Eq. 1.
-// Current implementation is fixed quadrature using mathext.integrate.quad.Fixed
+// The numerical integration is set by cos.Integration.
 func (cos FlatLCDM) ageIntegrate(z float64) (timeGyr float64) {
-	n := 1000 // Integration will be n-point Gaussian quadrature
-	Ol0 := 1 - cos.Om0
 	integrand := func(z float64) float64 {
-		denom := (1 + z) * math.Sqrt((1+z)*(1+z)*(1+cos.Om0*z)-z*(2+z)*Ol0)
+		denom := (1 + z) * cos.E(z)
 		return 1 / denom
 	}
-	// When given math.Inf(), quad.Fixed automatically redefines variables
-	// to successfully do the numerical integration.
-	return hubbleTime(cos.H0) * quad.Fixed(integrand, z, math.Inf(1), n, nil, 0)
+	return hubbleTime(cos.H0) * cos.Integration.integrate(integrand, z, math.Inf(1))
+}
+
+// analyzeExpansion finds the turning points from the roots of E^2(z)
+// if there is no radiation.
+func (cos FlatLCDM) analyzeExpansion() (e Expansion, ok bool) {
+	if (cos.Tcmb0 != 0) || !(cos.Om0 > 0) {
+		return Expansion{}, false
+	}
+	return expansionLambdaMatter(cos.Om0, 0, 1-cos.Om0), true
 }
 
 // E is the Hubble parameter as a fraction of its present value.
 // E.g., Hogg arXiv:9905116  Eq. 14
 func (cos FlatLCDM) E(z float64) (fractionalHubbleParameter float64) {
-	oR := cos.Ogamma0 + cos.Onu0
+	oR := cos.Ogamma0() * (1 + nuRelativeDensity(z, cos.Tcmb0, cos.Neff, cos.MNu))
 	deScale := 1.0
-	Ol0 := 1 - cos.Om0
+	Ol0 := 1 - (cos.Om0 + cos.Ogamma0() + cos.Onu0())
 	// I find it easier to explicitly // see the powers of 4, 3, and 0
 	// The compiler is good enough that this operation or
 	// 1+z vs. opz doesn't matter
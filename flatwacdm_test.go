@@ -0,0 +1,97 @@
+package cosmo
+
+import (
+	"strings"
+	"testing"
+)
+
+var zFlatWACDM = []float64{0.5, 1.0, 2.0, 3.0}
+
+// Calculated via Python AstroPy
+//   from astropy.cosmology import Flatw0waCDM
+//   z = np.asarray([0.5, 1.0, 2.0, 3.0])
+// These are identical to the flat w0waCDM(H0, Om0, 1-Om0, w0, wa) results in wacdm_test.go
+var testTableFlatWACDM = map[string]struct {
+	cos      FlatWACDM
+	function string
+	exp      []float64
+}{
+	//   Flatw0waCDM(70, 0.3, -1.2, 3).distmod(z)
+	"FlatWACDMDistanceModulus": {FlatWACDM{H0: 70, Om0: 0.3, W0: -1.2, WA: 3}, "DistanceModulus", []float64{42.20567831, 43.92122272, 45.57180818, 46.47483095}},
+	//   Flatw0waCDM(70, 0.3, -1.2, 0).distmod(z)
+	"FlatWACDMWcdmDistanceModulus": {FlatWACDM{H0: 70, Om0: 0.3, W0: -1.2, WA: 0}, "DistanceModulus", []float64{42.32710911, 44.17957201, 46.03118144, 47.09228735}},
+	//   Flatw0waCDM(70, 0.3, -0.9, 2).luminosity_distance(z)
+	"FlatWACDMLuminosityDistance": {FlatWACDM{H0: 70, Om0: 0.3, W0: -0.9, WA: 2}, "LuminosityDistance", []float64{2676.62203931, 5904.08905744, 12867.17142278, 19961.9490794}},
+	//   Flatw0waCDM(70, 0.3, -1, 0).luminosity_distance(z)
+	"FlatWACDMLuminosityDistanceFlatLCDM": {FlatWACDM{H0: 70, Om0: 0.3, W0: -1, WA: 0}, "LuminosityDistance", []float64{2832.9380939, 6607.65761177, 15539.58622323, 25422.74174519}},
+	//   Flatw0waCDM(70, 0.3, -0.8, 2.5).angular_diameter_distance(z)
+	"FlatWACDMAngularDiameterDistance": {FlatWACDM{H0: 70, Om0: 0.3, W0: -0.8, WA: 2.5}, "AngularDiameterDistance", []float64{1155.52181127, 1393.61319898, 1282.08090454, 1073.63096224}},
+	//   Flatw0waCDM(70, 1.0, -1, 0).comoving_distance(z)
+	"FlatWACDMComovingDistanceEdS": {FlatWACDM{H0: 70, Om0: 1.0, W0: -1, WA: 0}, "ComovingDistance", []float64{1571.79831586, 2508.77651427, 3620.20576208, 4282.7494}},
+	//   Flatw0waCDM(70, 0.3, -1.2, -1.2).comoving_transverse_distance(z)
+	"FlatWACDMComovingTransverseDistance": {FlatWACDM{H0: 70, Om0: 0.3, W0: -1.2, WA: -1.2}, "ComovingTransverseDistance", []float64{1985.54631561, 3533.91345688, 5524.66720808, 6731.56420461}},
+	//   Flatw0waCDM(70, 0.3, -0.9, 3.5)._comoving_distance_z1z2(0, z)
+	"FlatWACDMComovingDistanceZ1Z2Integrate": {FlatWACDM{H0: 70, Om0: 0.3, W0: -0.9, WA: 3.5}, "ComovingDistanceZ1Z2", []float64{1726.71519955, 2709.17698433, 3538.63486291, 3798.28908226}},
+	//   Flatw0waCDM(70, 0.3, -0.9, 3.5).lookback_time(z)
+	"FlatWACDMLookbackTime": {FlatWACDM{H0: 70, Om0: 0.3, W0: -0.9, WA: 3.5}, "LookbackTime", []float64{4.64427098, 6.51439755, 7.65559243, 7.90553458}},
+	//   Flatw0waCDM(70, 0.3, -1.1, 2.8).lookback_time(z)
+	"FlatWACDMLookbackTimeIntegrate": {FlatWACDM{H0: 70, Om0: 0.3, W0: -1.1, WA: 2.8}, "LookbackTime", []float64{4.86957219, 7.09750717, 8.81344126, 9.36817438}},
+	//   Flatw0waCDM(70, 0.3, -1, 0).age(z)
+	"FlatWACDMAgeFlatLCDM": {FlatWACDM{H0: 70, Om0: 0.3, W0: -1, WA: 0}, "Age", []float64{8.42634602, 5.75164694, 3.22662706, 2.11252719}},
+}
+
+func TestTableFlatWACDM(t *testing.T) {
+	for _, test := range testTableFlatWACDM {
+		switch {
+		case strings.HasSuffix(test.function, "Z1Z2"):
+			runTestsZ0Z2ByName(test.cos, test.function, zFlatWACDM, test.exp, distTol, t)
+		default:
+			runTestsByName(test.cos, test.function, zFlatWACDM, test.exp, distTol, t)
+		}
+	}
+}
+
+func TestFlatWACDMCosmologyInterface(t *testing.T) {
+	ageDistance := func(cos FLRW) {
+		z := 0.5
+		age := cos.Age(z)
+		dc := cos.ComovingDistance(z)
+		_, _ = age, dc
+	}
+
+	cos := FlatWACDM{H0: 70, Om0: 0.27, W0: -1, WA: 0.3}
+	ageDistance(cos)
+}
+
+// TestE* tests that basic calculation of E
+//   https://github.com/astropy/astropy/blob/master/astropy/cosmology/tests/test_cosmology.py
+func TestFlatWACDMELcdm(t *testing.T) {
+	cos := FlatWACDM{H0: 70, Om0: 0.27, W0: -1}
+	var z, exp float64
+
+	// Check value of E(z=1.0)
+	//   OM, OL, OK, z = 0.27, 0.73, 0.0, 1.0
+	//   sqrt(OM*(1+z)**3 + OK * (1+z)**2 + OL)
+	//   sqrt(0.27*(1+1.0)**3 + 0.0 * (1+1.0)**2 + 0.73)
+	//   sqrt(0.27*8 + 0 + 0.73)
+	//   sqrt(2.89)
+	z = 1.0
+	exp = 1.7
+	runTest(cos.E, z, exp, eTol, t, 0)
+
+	exp = 1 / 1.7
+	runTest(cos.Einv, z, exp, eTol, t, 0)
+}
+
+// TestFlatWACDMMatchesWACDM checks against WACDM with Ol0 = 1 - Om0 - Ogamma0 - Onu0,
+// including radiation, where WACDM has to go through the curvature logic.
+func TestFlatWACDMMatchesWACDM(t *testing.T) {
+	flat := FlatWACDM{H0: 70, Om0: 0.3, W0: -0.9, WA: 0.5, Tcmb0: 2.725, Neff: 3.04}
+	Ol0 := 1 - flat.Om0 - flat.Ogamma0() - flat.Onu0()
+	cos := WACDM{H0: 70, Om0: 0.3, Ol0: Ol0, W0: -0.9, WA: 0.5, Tcmb0: 2.725, Neff: 3.04}
+	for _, z := range zFlatWACDM {
+		runTest(flat.ComovingTransverseDistance, z, cos.ComovingTransverseDistance(z), distTol, t, 0)
+		runTest(flat.LookbackTime, z, cos.LookbackTime(z), ageTol, t, 0)
+		runTest(flat.Age, z, cos.Age(z), ageTol, t, 0)
+	}
+}
@@ -0,0 +1,313 @@
+package cosmo
+
+import (
+	"fmt"
+	"math"
+)
+
+// FlatWCDM provides cosmological distances, age, and look-back time
+// for a flat constant-w cosmology:
+// matter, dark energy, and no curvature,
+// with a w=constant equation-of-state parameter for dark energy.
+//
+// The dark energy density is Ol0 = 1 - Om0 - Ogamma0 - Onu0.
+type FlatWCDM struct {
+	H0          float64     // Hubble constant at z=0.  [km/s/Mpc]
+	Om0         float64     // Matter Density at z=0
+	Ob0         float64     // Baryon density at z=0, included in Om0
+	W0          float64     // Dark energy equation-of-state parameter, w = p/rho
+	Tcmb0       float64     // Temperature of the CMB at z=0.  [K]
+	Neff        float64     // Effective number of neutrino species
+	MNu         []float64   // Masses of the neutrino species.  [eV]
+	Integration Integration // Numerical integration of distances and times
+}
+
+func (cos FlatWCDM) String() string {
+	return fmt.Sprintf("FlatWCDM{H0: %v, Om0: %v, W0: %v}",
+		cos.H0, cos.Om0, cos.W0)
+}
+
+// NewFlatWCDM creates a FlatWCDM and validates its parameters.
+// The radiation, if any, is given as an optional Radiation
+// and validated with the other parameters.
+func NewFlatWCDM(H0, Om0, W0 float64, rad ...Radiation) (FlatWCDM, error) {
+	r, err := oneRadiation("FlatWCDM", rad)
+	if err != nil {
+		return FlatWCDM{}, err
+	}
+	cos := FlatWCDM{H0: H0, Om0: Om0, W0: W0, Tcmb0: r.Tcmb0, Neff: r.Neff, MNu: r.MNu}
+	if err := cos.Validate(); err != nil {
+		return FlatWCDM{}, err
+	}
+	return cos, nil
+}
+
+// Ok0 is the curvature density at z=0
+func (cos FlatWCDM) Ok0() (curvatureDensity float64) {
+	return 0
+}
+
+// integration is the numerical integration of distances and times
+func (cos FlatWCDM) integration() Integration {
+	return cos.Integration
+}
+
+// Ogamma0 is the photon density at z=0
+func (cos FlatWCDM) Ogamma0() (photonDensity float64) {
+	return ogamma0(cos.H0, cos.Tcmb0)
+}
+
+// Onu0 is the neutrino density at z=0
+func (cos FlatWCDM) Onu0() (neutrinoDensity float64) {
+	return onu0(cos.H0, cos.Tcmb0, cos.Neff, cos.MNu)
+}
+
+// Om is the matter density at z as a fraction of the critical density at z
+func (cos FlatWCDM) Om(z float64) (matterDensity float64) {
+	return cos.Om0 * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Ob is the baryon density at z as a fraction of the critical density at z
+func (cos FlatWCDM) Ob(z float64) (baryonDensity float64) {
+	return cos.Ob0 * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Tcmb is the temperature of the CMB at z.  [K]
+func (cos FlatWCDM) Tcmb(z float64) (temperatureK float64) {
+	return cos.Tcmb0 * (1 + z)
+}
+
+// Ode is the dark energy density at z as a fraction of the critical density at z
+func (cos FlatWCDM) Ode(z float64) (darkEnergyDensity float64) {
+	Ol0 := 1 - (cos.Om0 + cos.Ogamma0() + cos.Onu0())
+	return Ol0 * cos.deScale(z) / (cos.E(z) * cos.E(z))
+}
+
+// Ok is the curvature density at z as a fraction of the critical density at z
+func (cos FlatWCDM) Ok(z float64) (curvatureDensity float64) {
+	return cos.Ok0() * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Ogamma is the photon density at z as a fraction of the critical density at z
+func (cos FlatWCDM) Ogamma(z float64) (photonDensity float64) {
+	return cos.Ogamma0() * (1 + z) * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Onu is the neutrino density at z as a fraction of the critical density at z
+func (cos FlatWCDM) Onu(z float64) (neutrinoDensity float64) {
+	return cos.Ogamma0() * nuRelativeDensity(z, cos.Tcmb0, cos.Neff, cos.MNu) *
+		(1 + z) * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// H is the Hubble parameter at z.  [km/s/Mpc]
+func (cos FlatWCDM) H(z float64) (hubbleParameterKmSMpc float64) {
+	return cos.H0 * cos.E(z)
+}
+
+// CriticalDensity is the critical density at z.  [g/cm^3]
+func (cos FlatWCDM) CriticalDensity(z float64) (densityGCm3 float64) {
+	return criticalDensity0(cos.H0) * cos.E(z) * cos.E(z)
+}
+
+// CriticalDensityMsunMpc3 is the critical density at z.  [Msun/Mpc^3]
+func (cos FlatWCDM) CriticalDensityMsunMpc3(z float64) (densityMsunMpc3 float64) {
+	return cos.CriticalDensity(z) * gCm3ToMsunMpc3
+}
+
+// lambdaMatter returns Om0 and Ok0 if the cosmology has only
+// matter, curvature, and a cosmological constant, for which
+// the linear growth factor is given by the Heath integral.
+func (cos FlatWCDM) lambdaMatter() (Om0, Ok0 float64, ok bool) {
+	if cos.W0 != -1 {
+		return 0, 0, false
+	}
+	flatlcdm_cos := FlatLCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
+	return flatlcdm_cos.lambdaMatter()
+}
+
+// Validate checks that the parameters describe a physical cosmology.
+// The error, if any, is a *ParameterError for the first problem found.
+func (cos FlatWCDM) Validate() error {
+	const name = "FlatWCDM"
+	return firstError(
+		validateH0(name, cos.H0),
+		validateMatter(name, cos.Om0, cos.Ob0),
+		validateFinite(name, []string{"W0"}, cos.W0),
+		validateRadiation(name, cos.Tcmb0, cos.Neff, cos.MNu),
+		cos.Integration.validate(name),
+		validateFlat(name, cos.Om0, cos.Ogamma0(), cos.Onu0()),
+	)
+}
+
+// DistanceModulus is the magnitude difference between 1 Mpc and
+// the luminosity distance for the given z.
+func (cos FlatWCDM) DistanceModulus(z float64) (distanceModulusMag float64) {
+	return 5*math.Log10(cos.LuminosityDistance(z)) + 25
+}
+
+// LuminosityDistance is the radius of effective sphere over which the light has spread out
+func (cos FlatWCDM) LuminosityDistance(z float64) (distanceMpc float64) {
+	return (1 + z) * cos.ComovingTransverseDistance(z)
+}
+
+// AngularDiameterDistance is the ratio of physical transverse size to angular size
+func (cos FlatWCDM) AngularDiameterDistance(z float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistance(z) / (1 + z)
+}
+
+// AngularDiameterDistanceZ1Z2 is the angular diameter distance of an object at z2
+// as seen by an observer at z1, e.g., D_ls for a lens at z1 and a source at z2.
+func (cos FlatWCDM) AngularDiameterDistanceZ1Z2(z1, z2 float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistanceZ1Z2(z1, z2) / (1 + z2)
+}
+
+// ComovingTransverseDistance is the comoving distance at z as seen from z=0
+func (cos FlatWCDM) ComovingTransverseDistance(z float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistanceZ1Z2(0, z)
+}
+
+// ComovingTransverseDistanceZ1Z2 is the comoving distance at z2 as seen from z1
+//
+// With no curvature this is just the comoving distance.
+func (cos FlatWCDM) ComovingTransverseDistanceZ1Z2(z1, z2 float64) (distanceMpcRad float64) {
+	return cos.ComovingDistanceZ1Z2(z1, z2)
+}
+
+// ComovingVolume is the comoving volume of the whole sky out to z.
+func (cos FlatWCDM) ComovingVolume(z float64) (volumeMpc3 float64) {
+	return comovingVolume(cos, z)
+}
+
+// ComovingVolumeZ1Z2 is the comoving volume of the whole sky between z1 and z2.
+func (cos FlatWCDM) ComovingVolumeZ1Z2(z1, z2 float64) (volumeMpc3 float64) {
+	return cos.ComovingVolume(z2) - cos.ComovingVolume(z1)
+}
+
+// DifferentialComovingVolume is the comoving volume per unit redshift
+// per steradian at z.
+func (cos FlatWCDM) DifferentialComovingVolume(z float64) (volumeMpc3Sr float64) {
+	return differentialComovingVolume(cos, z)
+}
+
+// HubbleDistance is the inverse of the Hubble parameter
+//   distance : [Mpc]
+func (cos FlatWCDM) HubbleDistance() float64 {
+	return hubbleDistance(cos.H0)
+}
+
+// ComovingDistance is the distance that is constant with the Hubble flow
+// expressed in the physical distance at z=0.
+//
+// As the scale factor a = 1/(1+z) increases from 0.5 to 1,
+// two objects separated by a proper distance of 10 Mpc at a=0.5 (z=1)
+// will be separated by a proper distance of 2*10 Mpc at a=1.0 (z=0).
+// The comoving distance between these objects is 20 Mpc.
+func (cos FlatWCDM) ComovingDistance(z float64) (distanceMpc float64) {
+	return cos.ComovingDistanceZ1Z2(0, z)
+}
+
+// comovingDistanceZ1Z2Integrate is the comoving distance between two z
+// in a flat constant-w cosmology using the numerical integration of cos.Integration.
+func (cos FlatWCDM) comovingDistanceZ1Z2Integrate(z1, z2 float64) (distanceMpc float64) {
+	return cos.HubbleDistance() * cos.Integration.integrate(cos.Einv, z1, z2)
+}
+
+// ComovingDistanceZ1Z2 is the base function for calculation of comoving distances
+// Here is where the choice of fundamental calculation method is made:
+// Fall back to FlatLCDM's elliptic integral for w=-1, or quadature integration
+func (cos FlatWCDM) ComovingDistanceZ1Z2(z1, z2 float64) (distanceMpc float64) {
+	switch {
+	case cos.W0 == -1:
+		flatlcdm_cos := FlatLCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
+		return flatlcdm_cos.ComovingDistanceZ1Z2(z1, z2)
+	default:
+		return cos.comovingDistanceZ1Z2Integrate(z1, z2)
+	}
+}
+
+// LookbackTime is the time from redshift 0 to z.
+func (cos FlatWCDM) LookbackTime(z float64) (timeGyr float64) {
+	switch {
+	case cos.W0 == -1:
+		flatlcdm_cos := FlatLCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
+		return flatlcdm_cos.LookbackTime(z)
+	default:
+		return cos.lookbackTimeIntegrate(z)
+	}
+}
+
+// lookbackTimeIntegrate is the lookback time using explicit integration
+func (cos FlatWCDM) lookbackTimeIntegrate(z float64) (timeGyr float64) {
+	integrand := func(z float64) float64 { return cos.Einv(z) / (1 + z) }
+	return hubbleTime(cos.H0) * cos.Integration.integrate(integrand, 0, z)
+}
+
+// Age is the time from redshift ∞ to z.
+func (cos FlatWCDM) Age(z float64) (timeGyr float64) {
+	switch {
+	case cos.W0 == -1:
+		flatlcdm_cos := FlatLCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
+		return flatlcdm_cos.Age(z)
+	default:
+		return cos.ageIntegrate(z)
+	}
+}
+
+// zAtAge is the inverse of Age, for the cases in which Age is analytic.
+// ok is false for the other cases.
+func (cos FlatWCDM) zAtAge(timeGyr float64) (z float64, ok bool) {
+	if cos.W0 == -1 {
+		flatlcdm_cos := FlatLCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
+		return flatlcdm_cos.zAtAge(timeGyr)
+	}
+	return math.NaN(), false
+}
+
+// ageIntegrate is the time from redshift ∞ to z
+// using explicit integration.
+//
+// Basic integrand can be found in many texts
+// I happened to copy this from
+// Thomas and Kantowski, 2000, PRD, 62, 103507.  Eq. 1.
+// The numerical integration is set by cos.Integration.
+func (cos FlatWCDM) ageIntegrate(z float64) (timeGyr float64) {
+	integrand := func(z float64) float64 {
+		denom := (1 + z) * cos.E(z)
+		return 1 / denom
+	}
+	return hubbleTime(cos.H0) * cos.Integration.integrate(integrand, z, math.Inf(1))
+}
+
+// deScale is the dark energy density at z relative to z=0,
+//   (1+z)^(3(1+w0))
+func (cos FlatWCDM) deScale(z float64) (densityScale float64) {
+	if cos.W0 == -1 {
+		return 1
+	}
+	return math.Pow(1+z, 3*(1+cos.W0))
+}
+
+// E is the Hubble parameter as a fraction of its present value.
+// E.g., Hogg arXiv:9905116  Eq. 14
+func (cos FlatWCDM) E(z float64) (fractionalHubbleParameter float64) {
+	oR := cos.Ogamma0() * (1 + nuRelativeDensity(z, cos.Tcmb0, cos.Neff, cos.MNu))
+	deScale := cos.deScale(z)
+	Ol0 := 1 - (cos.Om0 + cos.Ogamma0() + cos.Onu0())
+	return math.Sqrt((1+z)*(1+z)*(1+z)*(1+z)*oR + (1+z)*(1+z)*(1+z)*cos.Om0 +
+		Ol0*deScale)
+}
+
+// Einv is the inverse Hubble parameter
+// Implementation is just to return E(z)
+func (cos FlatWCDM) Einv(z float64) (invFractionalHubbleParameter float64) {
+	// 1/Sqrt() is not notably slower than Pow(-0.5)
+	//
+	// Pow(-0.5) is in fact implemented as 1/Sqrt() in math.pow.go
+	// func pow(x, y float64) float64 {
+	//    [...]
+	// case y == -0.5:
+	//    return 1 / Sqrt(x)
+	//
+	// Thus we just return the inverse of E(z) instead of rewriting out here.
+	return 1 / cos.E(z)
+}
@@ -0,0 +1,106 @@
+package cosmo
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+var zFlatWCDM = []float64{0.5, 1.0, 2.0, 3.0}
+
+// Calculated via Python AstroPy
+//   from astropy.cosmology import FlatwCDM
+//   z = np.asarray([0.5, 1.0, 2.0, 3.0])
+// These are identical to the flat wCDM(H0, Om0, 1-Om0, w0) results in wcdm_test.go
+var testTableFlatWCDM = map[string]struct {
+	cos      FlatWCDM
+	function string
+	exp      []float64
+}{
+	//   FlatwCDM(70, 0.3, -1.2).distmod(z)
+	"FlatWCDMDistanceModulus": {FlatWCDM{H0: 70, Om0: 0.3, W0: -1.2}, "DistanceModulus", []float64{42.32710911, 44.17957201, 46.03118144, 47.09228735}},
+	//   FlatwCDM(70, 0.3, -1).luminosity_distance(z)
+	"FlatWCDMLuminosityDistanceFlatLCDM": {FlatWCDM{H0: 70, Om0: 0.3, W0: -1}, "LuminosityDistance", []float64{2832.9380939, 6607.65761177, 15539.58622323, 25422.74174519}},
+	//   FlatwCDM(70, 0.3, -1.1).luminosity_distance(z)
+	"FlatWCDMLuminosityDistance":           {FlatWCDM{H0: 70, Om0: 0.3, W0: -1.1}, "LuminosityDistance", []float64{2877.10314183, 6734.38177991, 15823.59621899, 25841.56448508}},
+	"FlatWCDMAngularDiameterDistance":      {FlatWCDM{H0: 70, Om0: 0.3, W0: -1}, "AngularDiameterDistance", []float64{1259.08359729, 1651.91440294, 1726.62069147, 1588.92135907}},
+	"FlatWCDMComovingTransverseDistance":   {FlatWCDM{H0: 70, Om0: 0.3, W0: -1}, "ComovingTransverseDistance", []float64{1888.62539593, 3303.82880589, 5179.86207441, 6355.6854363}},
+	"FlatWCDMComovingDistanceZ1Z2Elliptic": {FlatWCDM{H0: 70, Om0: 0.3, W0: -1}, "ComovingDistanceZ1Z2", []float64{1888.62539593, 3303.82880589, 5179.86207441, 6355.6854363}},
+	//   FlatwCDM(70, 1.0, -1).comoving_distance(z)
+	"FlatWCDMComovingDistanceEdS": {FlatWCDM{H0: 70, Om0: 1.0, W0: -1}, "ComovingDistance", []float64{1571.79831586, 2508.77651427, 3620.20576208, 4282.7494}},
+	//   FlatwCDM(70, 1.0, -0.8).comoving_distance(z)
+	"FlatWCDMComovingDistanceEdSIntegrate": {FlatWCDM{H0: 70, Om0: 1.0, W0: -0.8}, "ComovingDistance", []float64{1571.79831586, 2508.77651427, 3620.20576208, 4282.7494}},
+	//   FlatwCDM(70, 0.3, -1.2).lookback_time(z)
+	"FlatWCDMLookbackTime": {FlatWCDM{H0: 70, Om0: 0.3, W0: -1.2}, "LookbackTime", []float64{5.18796426, 7.98542226, 10.58842012, 11.71902479}},
+	//   FlatwCDM(70, 0.3, -1.1).lookback_time(z)
+	"FlatWCDMLookbackTimeIntegrate": {FlatWCDM{H0: 70, Om0: 0.3, W0: -1.1}, "LookbackTime", []float64{5.11509518, 7.85406053, 10.42213038, 11.54588106}},
+	//   FlatwCDM(70, 0.3, -1).age(z)
+	"FlatWCDMAgeFlatLCDM": {FlatWCDM{H0: 70, Om0: 0.3, W0: -1}, "Age", []float64{8.42634602, 5.75164694, 3.22662706, 2.11252719}},
+	//   FlatwCDM(70, 1.0, -0.8).age(z)
+	"FlatWCDMAgeEdS": {FlatWCDM{H0: 70, Om0: 1.0, W0: -0.8}, "Age", []float64{5.06897781, 3.29239767, 1.79215429, 1.16403836}},
+}
+
+func TestTableFlatWCDM(t *testing.T) {
+	for _, test := range testTableFlatWCDM {
+		switch {
+		case strings.HasSuffix(test.function, "Z1Z2"):
+			runTestsZ0Z2ByName(test.cos, test.function, zFlatWCDM, test.exp, distTol, t)
+		default:
+			runTestsByName(test.cos, test.function, zFlatWCDM, test.exp, distTol, t)
+		}
+	}
+}
+
+func TestFlatWCDMCosmologyInterface(t *testing.T) {
+	ageDistance := func(cos FLRW) {
+		z := 0.5
+		age := cos.Age(z)
+		dc := cos.ComovingDistance(z)
+		_, _ = age, dc
+	}
+
+	cos := FlatWCDM{H0: 70, Om0: 0.27, W0: -1.1}
+	ageDistance(cos)
+}
+
+// TestE* tests that basic calculation of E
+//   https://github.com/astropy/astropy/blob/master/astropy/cosmology/tests/test_cosmology.py
+func TestFlatWCDMELcdm(t *testing.T) {
+	cos := FlatWCDM{H0: 70, Om0: 0.27, W0: -1}
+	var z, exp float64
+
+	// Check value of E(z=1.0)
+	//   OM, OL, OK, z = 0.27, 0.73, 0.0, 1.0
+	//   sqrt(OM*(1+z)**3 + OK * (1+z)**2 + OL)
+	//   sqrt(0.27*(1+1.0)**3 + 0.0 * (1+1.0)**2 + 0.73)
+	//   sqrt(0.27*8 + 0 + 0.73)
+	//   sqrt(2.89)
+	z = 1.0
+	exp = 1.7
+	runTest(cos.E, z, exp, eTol, t, 0)
+
+	exp = 1 / 1.7
+	runTest(cos.Einv, z, exp, eTol, t, 0)
+}
+
+// TestFlatWCDMEW checks E(z) for w != -1
+//   sqrt(OM*(1+z)**3 + OL*(1+z)**(3*(1+w)))
+func TestFlatWCDMEW(t *testing.T) {
+	cos := FlatWCDM{H0: 70, Om0: 0.27, W0: -0.8}
+	z := 1.0
+	exp := math.Sqrt(0.27*8 + 0.73*math.Pow(2, 3*0.2))
+	runTest(cos.E, z, exp, eTol, t, 0)
+}
+
+// TestFlatWCDMMatchesWCDM checks against WCDM with Ol0 = 1 - Om0 - Ogamma0 - Onu0,
+// including radiation, where WCDM has to go through the curvature logic.
+func TestFlatWCDMMatchesWCDM(t *testing.T) {
+	flat := FlatWCDM{H0: 70, Om0: 0.3, W0: -0.9, Tcmb0: 2.725, Neff: 3.04}
+	Ol0 := 1 - flat.Om0 - flat.Ogamma0() - flat.Onu0()
+	cos := WCDM{H0: 70, Om0: 0.3, Ol0: Ol0, W0: -0.9, Tcmb0: 2.725, Neff: 3.04}
+	for _, z := range zFlatWCDM {
+		runTest(flat.ComovingTransverseDistance, z, cos.ComovingTransverseDistance(z), distTol, t, 0)
+		runTest(flat.LookbackTime, z, cos.LookbackTime(z), ageTol, t, 0)
+		runTest(flat.Age, z, cos.Age(z), ageTol, t, 0)
+	}
+}
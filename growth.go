@@ -0,0 +1,246 @@
+package cosmo
+
+import (
+	"math"
+)
+
+// GrowthFLRW is an FLRW that also knows its matter density,
+// which sources the growth of structure.
+type GrowthFLRW interface {
+	FLRW
+	Om(z float64) (matterDensity float64)
+}
+
+// lambdaMatterer is implemented by cosmologies that can report
+// whether they have only matter, curvature, and a cosmological constant.
+type lambdaMatterer interface {
+	lambdaMatter() (Om0, Ok0 float64, ok bool)
+}
+
+const (
+	growthAInit    = 1e-8  // Scale factor at which the growth ODE is started
+	growthStepInit = 0.1   // Initial step in ln(a) of the growth ODE
+	growthRelTol   = 1e-8  // Relative tolerance per step of the growth ODE
+	growthMaxSteps = 10000 // Maximum number of steps of the growth ODE
+)
+
+// GrowthFactor is the linear growth factor D(z) normalized to D(0) = 1.
+//
+// D(z) is the growing mode of the linear matter density contrast,
+//   d/da (a^3 E dD/da) = (3/2) Om0 D / (a^2 E)
+// solved numerically from E(z) and Om0.
+// For a cosmological constant without radiation,
+// D(z) is the Heath (1977) integral instead.
+// Only Om0 sources the growth; massive neutrinos do not cluster.
+func GrowthFactor(cos GrowthFLRW, z float64) (growthFactor float64) {
+	D, _, D0 := growthToday(cos, z)
+	return D / D0
+}
+
+// GrowthFactorEdS is the linear growth factor D(z) normalized to
+// the Einstein-de Sitter solution, D(z) = a = 1/(1+z),
+// at high redshift during matter domination.
+func GrowthFactorEdS(cos GrowthFLRW, z float64) (growthFactor float64) {
+	D, _ := growth(cos, z)
+	return D
+}
+
+// GrowthRate is the logarithmic growth rate f(z) = dln D / dln a.
+func GrowthRate(cos GrowthFLRW, z float64) (growthRate float64) {
+	_, f := growth(cos, z)
+	return f
+}
+
+// GrowthIndex is the growth index gamma(z), defined by f(z) = Om(z)^gamma.
+// gamma ~ 0.55 for a cosmological constant.
+func GrowthIndex(cos GrowthFLRW, z float64) (growthIndex float64) {
+	return math.Log(GrowthRate(cos, z)) / math.Log(cos.Om(z))
+}
+
+// FSigma8 is f(z) sigma8(z), the combination measured by redshift-space distortions,
+// given sigma8 at z=0.
+func FSigma8(cos GrowthFLRW, z, sigma8 float64) (fSigma8 float64) {
+	D, f, D0 := growthToday(cos, z)
+	return f * sigma8 * D / D0
+}
+
+// growth is the EdS-normalized growth factor and the growth rate at z.
+func growth(cos GrowthFLRW, z float64) (D, f float64) {
+	if lm, ok := cos.(lambdaMatterer); ok {
+		if Om0, Ok0, ok := lm.lambdaMatter(); ok {
+			return growthHeath(cos, Om0, Ok0, z)
+		}
+	}
+	D, f, _, _ = growthODE(cos, z, z)
+	return D, f
+}
+
+// growthToday is growth at z and the EdS-normalized growth factor at z=0,
+// from a single integration of the growth equation.
+func growthToday(cos GrowthFLRW, z float64) (D, f, D0 float64) {
+	if lm, ok := cos.(lambdaMatterer); ok {
+		if Om0, Ok0, ok := lm.lambdaMatter(); ok {
+			D, f = growthHeath(cos, Om0, Ok0, z)
+			D0, _ = growthHeath(cos, Om0, Ok0, 0)
+			return D, f, D0
+		}
+	}
+	D, f, D0, _ = growthODE(cos, z, 0)
+	return D, f, D0
+}
+
+// growthHeath is the growth factor and rate for matter, curvature,
+// and a cosmological constant:
+//   D(a) = (5/2) Om0 E(a) Integral_0^a da' / (a' E(a'))^3
+//   f = dln E / dln a + (5/2) Om0 / (a^2 E^2 D)
+//   Heath, 1977, MNRAS, 179, 351.  Eq. 8
+//   Eisenstein, 1997, https://arXiv.org/abs/astro-ph/9709054v2
+func growthHeath(cos FLRW, Om0, Ok0, z float64) (D, f float64) {
+	integrand := func(z float64) float64 {
+		E := cos.E(z)
+		return (1 + z) / (E * E * E)
+	}
+	I := integrationOf(cos).integrate(integrand, z, math.Inf(1))
+
+	opz := 1 + z
+	E := cos.E(z)
+	D = 2.5 * Om0 * E * I
+	dlnEdlna := -(3*Om0*opz*opz*opz + 2*Ok0*opz*opz) / (2 * E * E)
+	f = dlnEdlna + 2.5*Om0*opz*opz/(E*E*D)
+	return D, f
+}
+
+// growthODE integrates the growth equation in x = ln(a)
+// relative to the Meszaros (1974) solution during radiation and matter domination
+//   D_M = a + (2/3) a_eq,  P_M = a^3 E_M dD_M/da = a^3 E_M
+// which is the EdS normalization once matter dominates.
+// With P = a^3 E dD/da, y = (D/D_M, P/P_M), and r = E_M/E,
+//   dy0/dx = a/D_M (r y1 - y0)
+//   dy1/dx = (3/2) D_M/(a + a_eq) r y0 - (3/2 a + a_eq)/(a + a_eq) y1
+// starting from y = (1, 1).
+// It returns the growth factor and rate at both z1 and z2 from one pass.
+//
+// The steps are adapted to a relative error of growthRelTol per step
+// with the Dormand-Prince 5(4) Runge-Kutta method.
+// y stays (1, 1) until curvature or dark energy matter,
+// so the steps grow quickly from growthStepInit until then.
+//   Dormand & Prince, 1980, J. Comput. Appl. Math., 6, 19
+//   Press et al., Numerical Recipes, 3rd ed., Section 17.2
+func growthODE(cos GrowthFLRW, z1, z2 float64) (D1, f1, D2, f2 float64) {
+	Om0 := cos.Om(0)
+
+	// Radiation density, from what is left over in E(z) at the initial redshift.
+	opzInit := 1 / growthAInit
+	EInit := cos.E(opzInit - 1)
+	oR := (EInit*EInit - Om0*opzInit*opzInit*opzInit) / (opzInit * opzInit * opzInit * opzInit)
+	aEq := math.Max(oR, 0) / Om0
+
+	// r is E_M/E with E_M^2 = Om0 a^-3 (1 + a_eq/a).
+	r := func(a float64) float64 {
+		return math.Sqrt(Om0*(1+aEq/a)/(a*a*a)) / cos.E(1/a-1)
+	}
+	deriv := func(x float64, y [2]float64) [2]float64 {
+		a := math.Exp(x)
+		r := r(a)
+		DM := a + 2./3*aEq
+		return [2]float64{
+			a / DM * (r*y[1] - y[0]),
+			(1.5*DM*r*y[0] - (1.5*a+aEq)*y[1]) / (a + aEq),
+		}
+	}
+
+	x := math.Log(growthAInit)
+	y := [2]float64{1, 1}
+	h := growthStepInit
+	// at is D and f at z, integrating forwards in ln(a) to it.
+	at := func(z float64) (D, f float64) {
+		a := 1 / (1 + z)
+		DM := a + 2./3*aEq
+		if a <= growthAInit {
+			return DM, a / DM
+		}
+		x, y, h = dormandPrince(deriv, x, y, math.Log(a), h)
+		return DM * y[0], a * r(a) * y[1] / (DM * y[0])
+	}
+	if z1 >= z2 {
+		D1, f1 = at(z1)
+		D2, f2 = at(z2)
+	} else {
+		D2, f2 = at(z2)
+		D1, f1 = at(z1)
+	}
+	return D1, f1, D2, f2
+}
+
+// dormandPrince integrates dy/dx = deriv(x, y) from x0 to x1 >= x0
+// with adaptive Dormand-Prince 5(4) steps, starting with a step of h.
+// It returns x1, y(x1), and the step size to continue with.
+// y is NaN if the error is NaN or the steps exceed growthMaxSteps.
+func dormandPrince(deriv func(float64, [2]float64) [2]float64, x0 float64, y [2]float64, x1, h float64) (x float64, y1 [2]float64, hNext float64) {
+	const (
+		c2, c3, c4, c5 = 1. / 5, 3. / 10, 4. / 5, 8. / 9
+		a21            = 1. / 5
+		a31, a32       = 3. / 40, 9. / 40
+		a41, a42, a43  = 44. / 45, -56. / 15, 32. / 9
+		a51, a52, a53  = 19372. / 6561, -25360. / 2187, 64448. / 6561
+		a54            = -212. / 729
+		a61, a62, a63  = 9017. / 3168, -355. / 33, 46732. / 5247
+		a64, a65       = 49. / 176, -5103. / 18656
+		b1, b3, b4     = 35. / 384, 500. / 1113, 125. / 192
+		b5, b6         = -2187. / 6784, 11. / 84
+		e1, e3, e4     = 71. / 57600, -71. / 16695, 71. / 1920
+		e5, e6, e7     = -17253. / 339200, 22. / 525, -1. / 40
+	)
+	x = x0
+	for i := 0; (i < growthMaxSteps) && (x < x1); i++ {
+		last := x+h >= x1
+		if last {
+			h = x1 - x
+		}
+		var yt, y5 [2]float64
+		k1 := deriv(x, y)
+		for j := range yt {
+			yt[j] = y[j] + h*a21*k1[j]
+		}
+		k2 := deriv(x+c2*h, yt)
+		for j := range yt {
+			yt[j] = y[j] + h*(a31*k1[j]+a32*k2[j])
+		}
+		k3 := deriv(x+c3*h, yt)
+		for j := range yt {
+			yt[j] = y[j] + h*(a41*k1[j]+a42*k2[j]+a43*k3[j])
+		}
+		k4 := deriv(x+c4*h, yt)
+		for j := range yt {
+			yt[j] = y[j] + h*(a51*k1[j]+a52*k2[j]+a53*k3[j]+a54*k4[j])
+		}
+		k5 := deriv(x+c5*h, yt)
+		for j := range yt {
+			yt[j] = y[j] + h*(a61*k1[j]+a62*k2[j]+a63*k3[j]+a64*k4[j]+a65*k5[j])
+		}
+		k6 := deriv(x+h, yt)
+		for j := range y5 {
+			y5[j] = y[j] + h*(b1*k1[j]+b3*k3[j]+b4*k4[j]+b5*k5[j]+b6*k6[j])
+		}
+		k7 := deriv(x+h, y5)
+		errMax := 0.0
+		for j := range y5 {
+			e := h * (e1*k1[j] + e3*k3[j] + e4*k4[j] + e5*k5[j] + e6*k6[j] + e7*k7[j])
+			errMax = math.Max(errMax, math.Abs(e)/(growthRelTol*math.Max(math.Abs(y[j]), math.Abs(y5[j]))))
+		}
+		if math.IsNaN(errMax) {
+			break
+		}
+		if errMax <= 1 {
+			x, y = x+h, y5
+			if last {
+				x = x1
+			}
+		}
+		h *= math.Min(5, math.Max(0.2, 0.9*math.Pow(errMax, -0.2)))
+	}
+	if x < x1 {
+		return x1, [2]float64{math.NaN(), math.NaN()}, h
+	}
+	return x, y, h
+}
@@ -0,0 +1,155 @@
+package cosmo
+
+import (
+	"math"
+	"testing"
+)
+
+const growthTol = 1e-6
+
+// Calculated by direct midpoint integration of the Heath integral
+//   D(a) = (5/2) Om0 E(a) Integral_0^a da' / (a' E(a'))^3
+// for Om0 = 0.3, Ol0 = 0.7.
+func TestGrowthFlatLCDM(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	runTest(func(z float64) float64 { return GrowthFactorEdS(cos, z) }, 0, 0.77898102, growthTol, t, 0)
+	runTest(func(z float64) float64 { return GrowthFactor(cos, z) }, 0, 1, growthTol, t, 0)
+	runTest(func(z float64) float64 { return GrowthFactor(cos, z) }, 1, 0.61180575, growthTol, t, 0)
+	runTests(func(z float64) float64 { return GrowthRate(cos, z) }, []float64{0, 1}, []float64{0.51279625, 0.86928512}, growthTol, t)
+	runTest(func(z float64) float64 { return GrowthIndex(cos, z) }, 0, 0.55472739, growthTol, t, 0)
+	runTest(func(z float64) float64 { return FSigma8(cos, z, 0.8) }, 1, 0.86928512*0.8*0.61180575, growthTol, t, 0)
+}
+
+// In an Einstein-de Sitter universe D = a and f = 1,
+// both from the Heath integral and from the growth equation.
+func TestGrowthEdS(t *testing.T) {
+	zVec := []float64{0, 0.5, 1.0, 2.0, 3.0}
+	exp := make([]float64, len(zVec))
+	ones := make([]float64, len(zVec))
+	for i, z := range zVec {
+		exp[i] = 1 / (1 + z)
+		ones[i] = 1
+	}
+	for _, cos := range []GrowthFLRW{
+		FlatLCDM{H0: 70, Om0: 1},
+		WCDM{H0: 70, Om0: 1, Ol0: 0, W0: -0.9},
+	} {
+		runTests(func(z float64) float64 { return GrowthFactorEdS(cos, z) }, zVec, exp, growthTol, t)
+		runTests(func(z float64) float64 { return GrowthFactor(cos, z) }, zVec, exp, growthTol, t)
+		runTests(func(z float64) float64 { return GrowthRate(cos, z) }, zVec, ones, growthTol, t)
+	}
+}
+
+// TestGrowthODEHeath checks the growth equation against the Heath integral.
+func TestGrowthODEHeath(t *testing.T) {
+	for _, cos := range []GrowthFLRW{
+		FlatLCDM{H0: 70, Om0: 0.3},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.5},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9},
+		LambdaCDM{H0: 70, Om0: 1.5, Ol0: 0},
+	} {
+		Om0, Ok0, ok := cos.(lambdaMatterer).lambdaMatter()
+		if !ok {
+			t.Errorf("Expected %v to use the Heath integral", cos)
+		}
+		for _, z := range []float64{0, 0.5, 1.0, 3.0, 10.0} {
+			DHeath, fHeath := growthHeath(cos, Om0, Ok0, z)
+			D, f, _, _ := growthODE(cos, z, z)
+			runTest(func(float64) float64 { return D }, z, DHeath, growthTol, t, 0)
+			runTest(func(float64) float64 { return f }, z, fHeath, growthTol, t, 0)
+		}
+	}
+}
+
+// TestGrowthFallbacks checks that the dark energy types with w=-1
+// agree with LambdaCDM, and that w(z) models need the growth equation.
+func TestGrowthFallbacks(t *testing.T) {
+	exp := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.6}
+	for _, cos := range []GrowthFLRW{
+		WCDM{H0: 70, Om0: 0.3, Ol0: 0.6, W0: -1},
+		WACDM{H0: 70, Om0: 0.3, Ol0: 0.6, W0: -1, WA: 0},
+		WzCDM{H0: 70, Om0: 0.3, Ol0: 0.6},
+		WzCDM{H0: 70, Om0: 0.3, Ol0: 0.6, W: wConst(-1)},
+	} {
+		for _, z := range []float64{0.5, 1.0, 3.0} {
+			runTest(func(z float64) float64 { return GrowthFactor(cos, z) }, z, GrowthFactor(exp, z), growthTol, t, 0)
+			runTest(func(z float64) float64 { return GrowthRate(cos, z) }, z, GrowthRate(exp, z), growthTol, t, 0)
+		}
+	}
+	if _, _, ok := (FlatWACDM{H0: 70, Om0: 0.3, W0: -0.9, WA: 0.2}).lambdaMatter(); ok {
+		t.Errorf("Expected FlatWACDM with WA != 0 to need the growth equation")
+	}
+}
+
+// Radiation suppresses the growth at early times
+// but changes the growth rate today only by ~ Or0/Om0.
+func TestGrowthRadiation(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	rad := FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04}
+	for _, z := range []float64{0, 1.0, 3.0} {
+		runTest(func(z float64) float64 { return GrowthRate(rad, z) }, z, GrowthRate(cos, z), 1e-3, t, 0)
+		runTest(func(z float64) float64 { return GrowthFactor(rad, z) }, z, GrowthFactor(cos, z), 1e-3, t, 0)
+	}
+	// During matter domination D ~ a + (2/3) a_eq
+	aEq := (rad.Ogamma0() + rad.Onu0()) / rad.Om0
+	z := 100.0
+	exp := 1/(1+z) + 2./3*aEq
+	runTest(func(z float64) float64 { return GrowthFactorEdS(rad, z) }, z, exp, 1e-3*exp, t, 0)
+}
+
+func TestGrowthETable(t *testing.T) {
+	exp := FlatLCDM{H0: 70, Om0: 0.3}
+	z, E := makeETable(exp, 1e6, 1000)
+	table, err := NewETable(70, 0, z, E, InterpCubicSpline, ExtrapolatePowerLaw)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if g := GrowthFactor(table, 1); !math.IsNaN(g) {
+		t.Errorf("Expected NaN growth without Om0, got %v", g)
+	}
+	cos := table.WithOm0(0.3)
+	for _, z := range []float64{0.5, 1.0, 3.0} {
+		runTest(func(z float64) float64 { return GrowthFactor(cos, z) }, z, GrowthFactor(exp, z), 1e-5, t, 0)
+		runTest(func(z float64) float64 { return GrowthRate(cos, z) }, z, GrowthRate(exp, z), 1e-5, t, 0)
+	}
+}
+
+// TestWzCDMFuture checks E(z) of WzCDM for future redshifts, -1 < z < 0,
+// which the growth equation reaches through rounding at z=0.
+func TestWzCDMFuture(t *testing.T) {
+	cos := WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: wConst(-0.9)}
+	exp := WCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9}
+	runTests(cos.E, []float64{-0.5, -1e-16}, []float64{exp.E(-0.5), exp.E(-1e-16)}, eTol, t)
+}
+
+// One pass to both redshifts, in either order, matches separate integrations.
+func TestGrowthODEOnePass(t *testing.T) {
+	cos := WCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, Tcmb0: 2.725}
+	z1, z2 := 2.0, 0.0
+	D1, f1, _, _ := growthODE(cos, z1, z1)
+	D2, f2, _, _ := growthODE(cos, z2, z2)
+	for _, z := range [][2]float64{{z1, z2}, {z2, z1}} {
+		Da, fa, Db, fb := growthODE(cos, z[0], z[1])
+		if z[0] != z1 {
+			Da, fa, Db, fb = Db, fb, Da, fa
+		}
+		runTest(func(float64) float64 { return Da }, z1, D1, growthTol, t, 0)
+		runTest(func(float64) float64 { return fa }, z1, f1, growthTol, t, 0)
+		runTest(func(float64) float64 { return Db }, z2, D2, growthTol, t, 0)
+		runTest(func(float64) float64 { return fb }, z2, f2, growthTol, t, 0)
+	}
+}
+
+func BenchmarkGrowthFactorWCDM(b *testing.B) {
+	cos := WCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9}
+	for i := 0; i < b.N; i++ {
+		GrowthFactor(cos, 1)
+	}
+}
+
+func BenchmarkGrowthFactorWzCDM(b *testing.B) {
+	cos, _ := NewWzCDM(70, 0.3, 0.7, wLinder(-0.9, 0.2))
+	for i := 0; i < b.N; i++ {
+		GrowthFactor(cos, 1)
+	}
+}
@@ -0,0 +1,62 @@
+package halo
+
+import (
+	"github.com/wmwv/cosmo/powspec"
+	"math"
+)
+
+// BiasModel is a linear halo bias b(sigma, z) relative to the linear matter density field.
+type BiasModel interface {
+	Bias(cos powspec.Cosmology, sigma, z float64) (bias float64)
+}
+
+// Bias is the linear bias of halos of mass M at redshift z
+// for the linear power spectrum p.
+//   M : [Msun]
+func Bias(p powspec.Linear, model BiasModel, M, z float64) (bias float64) {
+	sigma := p.AtRedshift(z).SigmaM(M, powspec.WindowTopHat)
+	return model.Bias(p.Cosmology(), sigma, z)
+}
+
+// MoWhite is the peak-background split bias of the Press-Schechter mass function
+//   b = 1 + (nu^2 - 1) / delta_c
+//   Mo & White, 1996, MNRAS, 282, 347.  Eq. 20
+type MoWhite struct{}
+
+// Bias is b(sigma).
+func (MoWhite) Bias(cos powspec.Cosmology, sigma, z float64) (bias float64) {
+	nu := deltaC / sigma
+	return 1 + (nu*nu-1)/deltaC
+}
+
+// Bias is the peak-background split bias of the Sheth-Tormen mass function
+//   b = 1 + (a nu^2 - 1) / delta_c + 2p / (delta_c (1 + (a nu^2)^p))
+//   Sheth & Tormen, 1999, MNRAS, 308, 119.  Eq. 12
+func (ShethTormen) Bias(cos powspec.Cosmology, sigma, z float64) (bias float64) {
+	const a, p = 0.707, 0.3
+	nu := deltaC / sigma
+	anu2 := a * nu * nu
+	return 1 + (anu2-1)/deltaC + 2*p/(deltaC*(1+math.Pow(anu2, p)))
+}
+
+// Tinker10 is the bias of Tinker et al., 2010, ApJ, 724, 878,
+// calibrated on spherical overdensity halos.  Eq. 6, Table 2
+//   b = 1 - A nu^a / (nu^a + delta_c^a) + B nu^b + C nu^c
+// The parameters depend on y = log10 Delta, the overdensity relative to the mean density at z.
+type Tinker10 struct {
+	Definition Definition
+}
+
+// Bias is b(sigma, z).
+func (t Tinker10) Bias(cos powspec.Cosmology, sigma, z float64) (bias float64) {
+	y := math.Log10(t.Definition.DeltaMean(cos, z))
+	e := math.Exp(-math.Pow(4/y, 4))
+	A := 1 + 0.24*y*e
+	a := 0.44*y - 0.88
+	const B, b = 0.183, 1.5
+	C := 0.019 + 0.107*y + 0.19*e
+	const c = 2.4
+	nu := deltaC / sigma
+	nua := math.Pow(nu, a)
+	return 1 - A*nua/(nua+math.Pow(deltaC, a)) + B*math.Pow(nu, b) + C*math.Pow(nu, c)
+}
@@ -0,0 +1,54 @@
+package halo
+
+import (
+	"github.com/wmwv/cosmo/powspec"
+	"gonum.org/v1/gonum/integrate/quad"
+	"math"
+	"testing"
+)
+
+// The peak-background split bias of a mass function averages to one over all mass,
+//   Integral f(sigma) b(sigma) dln(1/sigma) = 1
+// for Press-Schechter with Mo-White and for Sheth-Tormen,
+// for which the mass function itself is only normalized to 1.0001.
+func TestBiasNormalization(t *testing.T) {
+	for _, c := range []struct {
+		mf  MassFunction
+		b   BiasModel
+		tol float64
+	}{
+		{PressSchechter{}, MoWhite{}, 1e-6},
+		{ShethTormen{}, ShethTormen{}, 1e-3},
+	} {
+		integrand := func(lnSigmaInv float64) float64 {
+			sigma := math.Exp(-lnSigmaInv)
+			return c.mf.Multiplicity(planck, sigma, 0) * c.b.Bias(planck, sigma, 0)
+		}
+		obs := quad.Fixed(integrand, -40, 3, 1000, nil, 0)
+		checkClose("Integral f b dln(1/sigma)", obs, 1, c.tol, t)
+	}
+}
+
+func TestBias(t *testing.T) {
+	// Halos at the collapse threshold, nu = 1, are unbiased in Mo & White.
+	checkClose("MoWhite(nu=1)", MoWhite{}.Bias(planck, deltaC, 0), 1, 1e-12, t)
+
+	// Tinker et al. 2010, Eq. 6 and Table 2, for Delta = 200 and nu = 1, evaluated by hand.
+	tinker := Tinker10{Definition{200, ReferenceMean}}
+	checkClose("Tinker10(nu=1)", tinker.Bias(planck, deltaC, 0), 0.9655, 1e-3, t)
+	crit := Tinker10{Definition{200 * planck.Om(0.5), ReferenceCritical}}
+	checkClose("Tinker10 critical", crit.Bias(planck, 0.8, 0.5), tinker.Bias(planck, 0.8, 0.5), 1e-12, t)
+
+	p := planckLinear(t)
+	for _, b := range []BiasModel{MoWhite{}, ShethTormen{}, tinker} {
+		sigma := p.AtRedshift(1).SigmaM(1e14, powspec.WindowTopHat)
+		checkClose("Bias", Bias(p, b, 1e14, 1), b.Bias(planck, sigma, 1), 1e-12, t)
+		// More massive halos, and halos of the same mass at higher redshift, are more biased.
+		if !(Bias(p, b, 1e15, 0) > Bias(p, b, 1e13, 0)) {
+			t.Errorf("Expected bias to increase with mass for %T", b)
+		}
+		if !(Bias(p, b, 1e14, 1) > Bias(p, b, 1e14, 0)) {
+			t.Errorf("Expected bias to increase with redshift for %T", b)
+		}
+	}
+}
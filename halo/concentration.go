@@ -0,0 +1,51 @@
+package halo
+
+import (
+	"github.com/wmwv/cosmo/powspec"
+	"math"
+)
+
+// ConcentrationMass is a concentration-mass relation c(M, z) of NFW halos.
+// Each relation is calibrated for a specific mass definition.
+type ConcentrationMass interface {
+	Concentration(cos powspec.Cosmology, M, z float64) (concentration float64)
+}
+
+// Duffy08 is the power-law concentration-mass relation of Duffy et al., 2008, MNRAS, 390, L64,
+//   c = A (M / M_pivot)^B (1+z)^C,  M_pivot = 2e12 Msun/h
+// calibrated on WMAP5 simulations for 1e11 < M < 1e15 Msun/h and z < 2.
+// Use Duffy08Critical200, Duffy08Virial, or Duffy08Mean200
+// with masses of the matching definition.
+type Duffy08 struct {
+	A, B, C float64
+}
+
+// Duffy08 parameters for the full sample of NFW halos, Table 1.
+var (
+	Duffy08Critical200 = Duffy08{A: 5.71, B: -0.084, C: -0.47}
+	Duffy08Virial      = Duffy08{A: 7.85, B: -0.081, C: -0.71}
+	Duffy08Mean200     = Duffy08{A: 10.14, B: -0.081, C: -1.01}
+)
+
+// Concentration is c(M, z).
+//   M : [Msun]
+func (d Duffy08) Concentration(cos powspec.Cosmology, M, z float64) (concentration float64) {
+	h := cos.H(0) / 100
+	return d.A * math.Pow(M*h/2e12, d.B) * math.Pow(1+z, d.C)
+}
+
+// DuttonMaccio14 is the concentration-mass relation of Dutton & Macciò, 2014, MNRAS, 441, 3359,
+// for NFW halos with M200c, calibrated on Planck simulations for z < 5.  Eqs. 7, 8
+//   log10 c = a + b log10(M / (1e12 Msun/h))
+//   a = 0.520 + (0.905 - 0.520) exp(-0.617 z^1.21)
+//   b = -0.101 + 0.026 z
+type DuttonMaccio14 struct{}
+
+// Concentration is c(M200c, z).
+//   M : [Msun]
+func (DuttonMaccio14) Concentration(cos powspec.Cosmology, M, z float64) (concentration float64) {
+	h := cos.H(0) / 100
+	a := 0.520 + (0.905-0.520)*math.Exp(-0.617*math.Pow(z, 1.21))
+	b := -0.101 + 0.026*z
+	return math.Pow(10, a+b*math.Log10(M*h/1e12))
+}
@@ -0,0 +1,53 @@
+// Package halo provides dark matter halo statistics built on the linear power spectrum
+// of package powspec.
+//
+// Halo masses are defined by a mean overdensity Delta within a sphere,
+// relative to either the mean matter density or the critical density at the redshift of the halo.
+// Masses are in Msun and number densities are comoving, in 1/Mpc^3, without factors of h.
+//
+// DnDM gives the halo mass function dn/dM for the multiplicity functions
+//   PressSchechter  Press & Schechter, 1974, ApJ, 187, 425
+//   ShethTormen     Sheth & Tormen, 1999, MNRAS, 308, 119
+//   Jenkins         Jenkins et al., 2001, MNRAS, 321, 372
+//   Tinker08        Tinker et al., 2008, ApJ, 688, 709
+// and Bias gives the linear halo bias for MoWhite, ShethTormen, and Tinker10.
+//
+// DeltaVirial is the Bryan & Norman (1998) virial overdensity.
+// NFW profiles follow from a mass, a concentration from a ConcentrationMass relation,
+// and a Definition, and ConvertMass moves between definitions, e.g., M200c and Mvir.
+package halo
+
+import "github.com/wmwv/cosmo/powspec"
+
+// deltaC is the linear overdensity for spherical collapse in an Einstein-de Sitter universe.
+const deltaC = 1.686
+
+// Reference is the density relative to which a halo overdensity is defined.
+type Reference int
+
+const (
+	// ReferenceMean is the mean matter density at the redshift of the halo.
+	ReferenceMean Reference = iota
+	// ReferenceCritical is the critical density at the redshift of the halo.
+	ReferenceCritical
+)
+
+// Definition is a spherical overdensity halo mass definition,
+// e.g., {200, ReferenceMean} for M200m or {500, ReferenceCritical} for M500c.
+type Definition struct {
+	Delta     float64
+	Reference Reference
+}
+
+// DeltaMean is the overdensity of the definition relative to the mean matter density at z.
+func (d Definition) DeltaMean(cos powspec.Cosmology, z float64) (deltaMean float64) {
+	if d.Reference == ReferenceCritical {
+		return d.Delta / cos.Om(z)
+	}
+	return d.Delta
+}
+
+// meanMatterDensity is the comoving mean matter density Om0 rho_crit,0.  [Msun/Mpc^3]
+func meanMatterDensity(cos powspec.Cosmology) (densityMsunMpc3 float64) {
+	return cos.Om(0) * cos.CriticalDensityMsunMpc3(0)
+}
@@ -0,0 +1,117 @@
+package halo
+
+import (
+	"github.com/wmwv/cosmo/powspec"
+	"math"
+)
+
+// MassFunction is a halo multiplicity function f(sigma, z), defined by
+//   dn/dM = f(sigma) rho_m / M dln(1/sigma) / dM
+// where sigma(M, z) is the rms linear density fluctuation in a top-hat enclosing M.
+type MassFunction interface {
+	Multiplicity(cos powspec.Cosmology, sigma, z float64) (f float64)
+}
+
+// DnDM is the comoving number density of halos per unit mass at mass M and redshift z
+// for the linear power spectrum p.  [1/Mpc^3/Msun]
+//   M : [Msun]
+func DnDM(p powspec.Linear, mf MassFunction, M, z float64) (dndM float64) {
+	return DnDlnM(p, mf, M, z) / M
+}
+
+// DnDlnM is the comoving number density of halos per unit ln M
+// at mass M and redshift z for the linear power spectrum p.  [1/Mpc^3]
+//   M : [Msun]
+func DnDlnM(p powspec.Linear, mf MassFunction, M, z float64) (dndlnM float64) {
+	cos := p.Cosmology()
+	pz := p.AtRedshift(z)
+	sigma := pz.SigmaM(M, powspec.WindowTopHat)
+	// dln sigma / dln M does not depend on redshift.
+	dlnSigmaDlnM := p.DlnSigmaDlnM(M, powspec.WindowTopHat)
+	return mf.Multiplicity(cos, sigma, z) * meanMatterDensity(cos) / M * -dlnSigmaDlnM
+}
+
+// PressSchechter is the mass function of Press & Schechter, 1974, ApJ, 187, 425.
+//   f = sqrt(2/pi) nu exp(-nu^2/2),  nu = delta_c / sigma
+// It assumes spherical collapse and does not depend on the halo definition.
+type PressSchechter struct{}
+
+// Multiplicity is f(sigma).
+func (PressSchechter) Multiplicity(cos powspec.Cosmology, sigma, z float64) (f float64) {
+	nu := deltaC / sigma
+	return math.Sqrt(2/math.Pi) * nu * math.Exp(-nu*nu/2)
+}
+
+// ShethTormen is the ellipsoidal-collapse mass function of Sheth & Tormen, 1999, MNRAS, 308, 119.
+//   f = A sqrt(2a/pi) (1 + (a nu^2)^-p) nu exp(-a nu^2/2)
+// with A = 0.3222, a = 0.707, p = 0.3.
+// It was fit to halos of about 180 times the mean density.
+type ShethTormen struct{}
+
+// Multiplicity is f(sigma).
+func (ShethTormen) Multiplicity(cos powspec.Cosmology, sigma, z float64) (f float64) {
+	const A, a, p = 0.3222, 0.707, 0.3
+	nu := deltaC / sigma
+	anu2 := a * nu * nu
+	return A * math.Sqrt(2*a/math.Pi) * (1 + math.Pow(anu2, -p)) * nu * math.Exp(-anu2/2)
+}
+
+// Jenkins is the mass function of Jenkins et al., 2001, MNRAS, 321, 372.  Eq. 9
+//   f = 0.315 exp(-|ln(1/sigma) + 0.61|^3.8)
+// for friends-of-friends halos with linking length 0.2,
+// valid for -1.2 < ln(1/sigma) < 1.05.
+type Jenkins struct{}
+
+// Multiplicity is f(sigma).
+func (Jenkins) Multiplicity(cos powspec.Cosmology, sigma, z float64) (f float64) {
+	return 0.315 * math.Exp(-math.Pow(math.Abs(-math.Log(sigma)+0.61), 3.8))
+}
+
+// Tinker08 is the mass function of Tinker et al., 2008, ApJ, 688, 709,
+// for spherical overdensity halos.
+//   f = A ((sigma/b)^-a + 1) exp(-c/sigma^2)
+// The parameters are interpolated linearly in ln Delta in Table 2
+// for the overdensity relative to the mean density at z, 200 <= Delta_m <= 3200,
+// and evolve with redshift following Eqs. 5-8.
+// f is NaN outside of the calibrated range of Delta_m.
+type Tinker08 struct {
+	Definition Definition
+}
+
+// tinker08Delta, A, a, b, c are Tinker et al. 2008, Table 2.
+var (
+	tinker08Delta = []float64{200, 300, 400, 600, 800, 1200, 1600, 2400, 3200}
+	tinker08A     = []float64{0.186, 0.200, 0.212, 0.218, 0.248, 0.255, 0.260, 0.260, 0.260}
+	tinker08a     = []float64{1.47, 1.52, 1.56, 1.61, 1.87, 2.13, 2.30, 2.53, 2.66}
+	tinker08b     = []float64{2.57, 2.25, 2.05, 1.87, 1.59, 1.51, 1.46, 1.44, 1.41}
+	tinker08c     = []float64{1.19, 1.27, 1.34, 1.45, 1.58, 1.80, 1.97, 2.24, 2.44}
+)
+
+// Multiplicity is f(sigma, z).
+func (t Tinker08) Multiplicity(cos powspec.Cosmology, sigma, z float64) (f float64) {
+	delta := t.Definition.DeltaMean(cos, z)
+	A0, a0, b0, c := tinker08Params(delta)
+
+	// Eqs. 5-8
+	opz := 1 + z
+	alpha := math.Pow(10, -math.Pow(0.75/math.Log10(delta/75), 1.2))
+	A := A0 * math.Pow(opz, -0.14)
+	a := a0 * math.Pow(opz, -0.06)
+	b := b0 * math.Pow(opz, -alpha)
+	return A * (math.Pow(sigma/b, -a) + 1) * math.Exp(-c/(sigma*sigma))
+}
+
+// tinker08Params interpolates Table 2 of Tinker et al. 2008 linearly in ln Delta.
+func tinker08Params(delta float64) (A, a, b, c float64) {
+	n := len(tinker08Delta)
+	if !(delta >= tinker08Delta[0]) || !(delta <= tinker08Delta[n-1]) {
+		return math.NaN(), math.NaN(), math.NaN(), math.NaN()
+	}
+	i := 1
+	for (i < n-1) && (tinker08Delta[i] < delta) {
+		i++
+	}
+	x := math.Log(delta/tinker08Delta[i-1]) / math.Log(tinker08Delta[i]/tinker08Delta[i-1])
+	interp := func(y []float64) float64 { return y[i-1] + x*(y[i]-y[i-1]) }
+	return interp(tinker08A), interp(tinker08a), interp(tinker08b), interp(tinker08c)
+}
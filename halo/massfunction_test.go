@@ -0,0 +1,103 @@
+package halo
+
+import (
+	"github.com/wmwv/cosmo"
+	"github.com/wmwv/cosmo/powspec"
+	"gonum.org/v1/gonum/integrate/quad"
+	"math"
+	"testing"
+)
+
+// planck is close to the Planck 2015 TT,TE,EE+lowP+lensing+ext cosmology.
+var planck = cosmo.FlatLCDM{H0: 67.74, Om0: 0.3089, Ob0: 0.0486, Tcmb0: 2.7255, Neff: 3.046}
+
+func checkClose(name string, obs, exp, tol float64, t *testing.T) {
+	t.Helper()
+	if !(math.Abs(obs-exp) <= tol) {
+		t.Errorf("%s: expected %v +/- %v, got %v", name, exp, tol, obs)
+	}
+}
+
+func planckLinear(t *testing.T) powspec.Linear {
+	t.Helper()
+	eh, err := powspec.NewEisensteinHu(planck)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	p, err := powspec.NewLinearSigma8(planck, eh, 0.9667, 0.8159)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	return p
+}
+
+// All mass is in halos for Press-Schechter,
+//   Integral f(sigma) dln(1/sigma) = 1
+// and for Sheth-Tormen
+//   A (1 + 2^-p Gamma(1/2 - p) / sqrt(pi)) = 1.0001
+func TestMultiplicityNormalization(t *testing.T) {
+	for _, c := range []struct {
+		mf  MassFunction
+		exp float64
+	}{
+		{PressSchechter{}, 1},
+		{ShethTormen{}, 0.3222 * (1 + math.Pow(2, -0.3)*math.Gamma(0.2)/math.Sqrt(math.Pi))},
+	} {
+		integrand := func(lnSigmaInv float64) float64 {
+			return c.mf.Multiplicity(planck, math.Exp(-lnSigmaInv), 0)
+		}
+		obs := quad.Fixed(integrand, -40, 3, 1000, nil, 0)
+		checkClose("Integral f dln(1/sigma)", obs, c.exp, 1e-6, t)
+	}
+}
+
+func TestMultiplicity(t *testing.T) {
+	checkClose("Jenkins", Jenkins{}.Multiplicity(planck, 1, 0), 0.315*math.Exp(-math.Pow(0.61, 3.8)), 1e-12, t)
+
+	// At a tabulated Delta and z=0, Tinker08 is the Table 2 fit.
+	m200 := Tinker08{Definition{200, ReferenceMean}}
+	exp := 0.186 * (math.Pow(1/2.57, -1.47) + 1) * math.Exp(-1.19)
+	checkClose("Tinker08", m200.Multiplicity(planck, 1, 0), exp, 1e-12, t)
+
+	// 200 times the mean density is 200 Om(z) times the critical density.
+	for _, z := range []float64{0, 0.5, 1} {
+		c := Tinker08{Definition{200 * planck.Om(z), ReferenceCritical}}
+		checkClose("Tinker08 critical", c.Multiplicity(planck, 0.8, z), m200.Multiplicity(planck, 0.8, z), 1e-12, t)
+	}
+
+	// Interpolation in between tabulated values.
+	m250 := Tinker08{Definition{250, ReferenceMean}}.Multiplicity(planck, 1, 0)
+	m300 := Tinker08{Definition{300, ReferenceMean}}.Multiplicity(planck, 1, 0)
+	if !(math.Min(exp, m300) < m250) || !(m250 < math.Max(exp, m300)) {
+		t.Errorf("Expected Tinker08 at Delta=250 to be between Delta=200 and 300")
+	}
+
+	if f := (Tinker08{Definition{100, ReferenceMean}}).Multiplicity(planck, 1, 0); !math.IsNaN(f) {
+		t.Errorf("Expected NaN for Tinker08 outside of the calibrated Delta, got %v", f)
+	}
+}
+
+func TestDnDM(t *testing.T) {
+	p := planckLinear(t)
+	// The mean matter density is 2.775e11 h^2 Om0 Msun/Mpc^3.
+	h := planck.H0 / 100
+	rho := 2.77536627e11 * h * h * planck.Om0
+	checkClose("rho_m", meanMatterDensity(planck), rho, 1e-5*rho, t)
+
+	M, z := 1e14, 0.5
+	sigma := p.AtRedshift(z).SigmaM(M, powspec.WindowTopHat)
+	slope := p.DlnSigmaDlnM(M, powspec.WindowTopHat)
+	for _, mf := range []MassFunction{PressSchechter{}, ShethTormen{}, Jenkins{}, Tinker08{Definition{200, ReferenceMean}}} {
+		exp := mf.Multiplicity(planck, sigma, z) * rho / (M * M) * -slope
+		checkClose("DnDM", DnDM(p, mf, M, z), exp, 1e-5*exp, t)
+		checkClose("DnDlnM", DnDlnM(p, mf, M, z), M*exp, 1e-5*M*exp, t)
+
+		// Massive halos are rarer, and rarer still in the past.
+		if !(DnDlnM(p, mf, 1e15, 0) < DnDlnM(p, mf, 1e14, 0)) {
+			t.Errorf("Expected fewer halos of 1e15 Msun than of 1e14 Msun for %T", mf)
+		}
+		if !(DnDlnM(p, mf, 1e15, 1) < DnDlnM(p, mf, 1e15, 0)) {
+			t.Errorf("Expected fewer halos of 1e15 Msun at z=1 than at z=0 for %T", mf)
+		}
+	}
+}
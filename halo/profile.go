@@ -0,0 +1,131 @@
+package halo
+
+import (
+	"github.com/wmwv/cosmo/powspec"
+	"math"
+)
+
+// DeltaVirial is the virial overdensity relative to the critical density at z
+// for spherical collapse with a cosmological constant,
+//   Delta_c = 18 pi^2 + 82 x - 39 x^2,  x = Om(z) - 1
+//   Bryan & Norman, 1998, ApJ, 495, 80.  Eq. 6
+// It is ~ 18 pi^2 during matter domination and ~ 100 today for Om0 = 0.3.
+func DeltaVirial(cos powspec.Cosmology, z float64) (deltaCritical float64) {
+	x := cos.Om(z) - 1
+	return 18*math.Pi*math.Pi + 82*x - 39*x*x
+}
+
+// Virial is the virial mass definition at z of Bryan & Norman (1998).
+func Virial(cos powspec.Cosmology, z float64) Definition {
+	return Definition{Delta: DeltaVirial(cos, z), Reference: ReferenceCritical}
+}
+
+// Density is the mean density within the halo boundary at z, Delta rho_ref(z).  [Msun/Mpc^3]
+// This is a physical, not a comoving, density.
+func (d Definition) Density(cos powspec.Cosmology, z float64) (densityMsunMpc3 float64) {
+	if d.Reference == ReferenceCritical {
+		return d.Delta * cos.CriticalDensityMsunMpc3(z)
+	}
+	opz := 1 + z
+	return d.Delta * meanMatterDensity(cos) * opz * opz * opz
+}
+
+// Radius is the physical radius of a halo of mass M at z,
+//   M = (4 pi / 3) Delta rho_ref(z) R^3
+//   M : [Msun]
+//   R : [Mpc]
+func (d Definition) Radius(cos powspec.Cosmology, M, z float64) (radiusMpc float64) {
+	return math.Cbrt(3 * M / (4 * math.Pi * d.Density(cos, z)))
+}
+
+// Mass is the mass of a halo of physical radius R at z.
+//   R : [Mpc]
+//   M : [Msun]
+func (d Definition) Mass(cos powspec.Cosmology, R, z float64) (massMsun float64) {
+	return 4 * math.Pi / 3 * d.Density(cos, z) * R * R * R
+}
+
+// ConvertMass converts the mass M and concentration c of an NFW halo at z
+// from one mass definition to another, e.g., from M200c to Mvir.
+//   M : [Msun]
+func ConvertMass(cos powspec.Cosmology, M, c float64, from, to Definition, z float64) (massMsun, concentration float64) {
+	nfw := NewNFW(cos, M, c, from, z)
+	// The mean density within r, 3 M(<r) / (4 pi r^3), decreases monotonically with r.
+	target := to.Density(cos, z)
+	f := func(lnx float64) float64 {
+		r := nfw.Rs * math.Exp(lnx)
+		return 3*nfw.EnclosedMass(r)/(4*math.Pi*r*r*r) - target
+	}
+	lo, hi := math.Log(c)-1, math.Log(c)+1
+	for f(lo) < 0 {
+		lo--
+	}
+	for f(hi) > 0 {
+		hi++
+	}
+	for hi-lo > 1e-12 {
+		mid := (lo + hi) / 2
+		if f(mid) > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	x := math.Exp((lo + hi) / 2)
+	return nfw.EnclosedMass(x * nfw.Rs), x
+}
+
+// NFW is the density profile of Navarro, Frenk, & White, 1997, ApJ, 490, 493,
+//   rho(r) = rho_s / ((r/r_s) (1 + r/r_s)^2)
+type NFW struct {
+	Rs   float64 // Scale radius.  [Mpc] physical
+	RhoS float64 // Characteristic density.  [Msun/Mpc^3] physical
+}
+
+// NewNFW is the NFW profile of a halo of mass M and concentration c = R/r_s
+// for the mass definition def at z.
+//   M : [Msun]
+func NewNFW(cos powspec.Cosmology, M, c float64, def Definition, z float64) NFW {
+	rs := def.Radius(cos, M, z) / c
+	return NFW{Rs: rs, RhoS: M / (4 * math.Pi * rs * rs * rs * nfwMass(c))}
+}
+
+// nfwMass is the dimensionless enclosed mass M(<x r_s) / (4 pi rho_s r_s^3)
+func nfwMass(x float64) float64 {
+	return math.Log1p(x) - x/(1+x)
+}
+
+// Density is the density at physical radius r.  [Msun/Mpc^3]
+//   r : [Mpc]
+func (h NFW) Density(r float64) (densityMsunMpc3 float64) {
+	x := r / h.Rs
+	return h.RhoS / (x * (1 + x) * (1 + x))
+}
+
+// EnclosedMass is the mass within a sphere of physical radius r.  [Msun]
+//   r : [Mpc]
+func (h NFW) EnclosedMass(r float64) (massMsun float64) {
+	return 4 * math.Pi * h.RhoS * h.Rs * h.Rs * h.Rs * nfwMass(r/h.Rs)
+}
+
+// SurfaceDensity is the projected surface density at physical projected radius R
+// of the untruncated profile.  [Msun/Mpc^2]
+//   Sigma(x) = 2 rho_s r_s / (x^2 - 1) (1 - F(x)),  x = R / r_s
+//   Wright & Brainerd, 2000, ApJ, 534, 34.  Eq. 11
+//   R : [Mpc]
+func (h NFW) SurfaceDensity(R float64) (densityMsunMpc2 float64) {
+	x := R / h.Rs
+	var F float64
+	switch {
+	case math.Abs(x-1) < 1e-4:
+		// Series about x = 1
+		return 2 * h.RhoS * h.Rs * (1./3 - 0.4*(x-1))
+	case x < 1:
+		s := math.Sqrt(1 - x*x)
+		F = math.Atanh(s) / s
+	default:
+		s := math.Sqrt(x*x - 1)
+		F = math.Atan(s) / s
+	}
+	return 2 * h.RhoS * h.Rs * (1 - F) / (x*x - 1)
+}
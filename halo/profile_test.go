@@ -0,0 +1,96 @@
+package halo
+
+import (
+	"github.com/wmwv/cosmo"
+	"gonum.org/v1/gonum/integrate/quad"
+	"math"
+	"testing"
+)
+
+func TestDeltaVirial(t *testing.T) {
+	// Om0 = 0.3: x = -0.7, 18 pi^2 - 82 * 0.7 - 39 * 0.49
+	cos := cosmo.FlatLCDM{H0: 70, Om0: 0.3}
+	checkClose("DeltaVirial(0)", DeltaVirial(cos, 0), 18*math.Pi*math.Pi-57.4-19.11, 1e-10, t)
+	eds := cosmo.FlatLCDM{H0: 70, Om0: 1}
+	checkClose("DeltaVirial EdS", DeltaVirial(eds, 2), 18*math.Pi*math.Pi, 1e-10, t)
+	checkClose("DeltaVirial(1000)", DeltaVirial(cos, 1000), 18*math.Pi*math.Pi, 1e-3, t)
+}
+
+func TestDefinition(t *testing.T) {
+	z := 0.5
+	m200c := Definition{200, ReferenceCritical}
+	m200m := Definition{200, ReferenceMean}
+	h := planck.H0 / 100
+	rhoCrit := 2.77536627e11 * h * h * planck.E(z) * planck.E(z)
+	checkClose("Density", m200c.Density(planck, z), 200*rhoCrit, 1e-5*200*rhoCrit, t)
+	checkClose("Density", m200m.Density(planck, z), 200*planck.Om(z)*rhoCrit, 1e-5*200*rhoCrit, t)
+
+	M := 1e14
+	R := m200c.Radius(planck, M, z)
+	checkClose("Mass", m200c.Mass(planck, R, z), M, 1e-10*M, t)
+	checkClose("DeltaMean", m200c.DeltaMean(planck, z), 200/planck.Om(z), 1e-10, t)
+
+	vir := Virial(planck, z)
+	checkClose("Virial", vir.Delta, DeltaVirial(planck, z), 0, t)
+}
+
+func TestNFW(t *testing.T) {
+	z := 0.3
+	def := Definition{200, ReferenceCritical}
+	M, c := 1e14, 5.0
+	nfw := NewNFW(planck, M, c, def, z)
+	R := def.Radius(planck, M, z)
+	checkClose("Rs", nfw.Rs, R/c, 1e-12, t)
+	checkClose("EnclosedMass(R200)", nfw.EnclosedMass(R), M, 1e-10*M, t)
+
+	n := 1000
+	mass := quad.Fixed(func(r float64) float64 { return 4 * math.Pi * r * r * nfw.Density(r) }, 0, R, n, nil, 0)
+	checkClose("Integral 4 pi r^2 rho", mass, M, 1e-6*M, t)
+
+	// Sigma(R) = 2 Integral_0^inf rho(sqrt(R^2 + l^2)) dl, across x = R/r_s = 1.
+	for _, x := range []float64{0.1, 0.5, 0.99995, 1, 1.00005, 2, 10} {
+		Rp := x * nfw.Rs
+		exp := 2 * quad.Fixed(func(l float64) float64 { return nfw.Density(math.Hypot(Rp, l)) }, 0, math.Inf(1), n, nil, 0)
+		checkClose("SurfaceDensity", nfw.SurfaceDensity(Rp), exp, 1e-6*exp, t)
+	}
+}
+
+func TestConvertMass(t *testing.T) {
+	z := 0.5
+	m200c := Definition{200, ReferenceCritical}
+	m500c := Definition{500, ReferenceCritical}
+	vir := Virial(planck, z)
+	M, c := 1e14, 4.0
+
+	// Larger overdensities enclose less mass at higher concentration.
+	M500, c500 := ConvertMass(planck, M, c, m200c, m500c, z)
+	if !(M500 < M) || !(c500 < c) {
+		t.Errorf("Expected M500c < M200c and c500c < c200c, got %v, %v", M500, c500)
+	}
+	Mvir, cvir := ConvertMass(planck, M, c, m200c, vir, z)
+	if !(Mvir > M) || !(cvir > c) {
+		t.Errorf("Expected Mvir > M200c and cvir > c200c, got %v, %v", Mvir, cvir)
+	}
+	// The scale radius is the same.
+	checkClose("r_s", vir.Radius(planck, Mvir, z)/cvir, m200c.Radius(planck, M, z)/c, 1e-9, t)
+
+	back, cBack := ConvertMass(planck, Mvir, cvir, vir, m200c, z)
+	checkClose("Round trip M", back, M, 1e-8*M, t)
+	checkClose("Round trip c", cBack, c, 1e-8, t)
+}
+
+func TestConcentration(t *testing.T) {
+	h := planck.H0 / 100
+	// At the pivot mass, c = A (1+z)^C
+	M := 2e12 / h
+	checkClose("Duffy08", Duffy08Critical200.Concentration(planck, M, 1), 5.71*math.Pow(2, -0.47), 1e-10, t)
+	// At 1e12 Msun/h, log10 c = a
+	M = 1e12 / h
+	checkClose("DuttonMaccio14", DuttonMaccio14{}.Concentration(planck, M, 0), math.Pow(10, 0.905), 1e-10, t)
+
+	for _, cm := range []ConcentrationMass{Duffy08Critical200, Duffy08Virial, Duffy08Mean200, DuttonMaccio14{}} {
+		if !(cm.Concentration(planck, 1e15, 0) < cm.Concentration(planck, 1e12, 0)) {
+			t.Errorf("Expected concentration to decrease with mass for %v", cm)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package cosmo
+
+import (
+	"math"
+)
+
+// ConformalTime is the conformal time from redshift ∞ to z,
+//   eta = Integral_0^t dt'/a(t') = 1/H0 Integral_z^∞ dz'/E(z')
+// i.e., the time light would need to cross the particle horizon
+// if the universe stopped expanding.  [Gyr]
+// It is +Inf if the integral diverges, as it does for a universe
+// without matter or radiation, which has no big bang.
+// The numerical integration is set by the Integration of cos.
+func ConformalTime(cos FLRW, z float64) (timeGyr float64) {
+	return hubbleTime(SpeedOfLightKmS/cos.HubbleDistance()) * conformalIntegral(cos, z)
+}
+
+// ParticleHorizon is the comoving distance to the particle horizon at z,
+// the farthest an object can be and still have been seen by z,
+//   D_p = c eta = D_H Integral_z^∞ dz'/E(z')
+// The proper distance is D_p / (1+z).
+// It is +Inf if the integral diverges, as for ConformalTime.
+// The numerical integration is set by the Integration of cos.
+func ParticleHorizon(cos FLRW, z float64) (distanceMpc float64) {
+	return cos.HubbleDistance() * conformalIntegral(cos, z)
+}
+
+// EventHorizon is the comoving distance to the event horizon at z,
+// the farthest an object can be at z and still be seen some time in the future,
+//   D_e = D_H Integral_{-1}^z dz'/E(z')
+// The proper distance is D_e / (1+z).
+// It is finite only if the expansion accelerates forever, e.g., with a cosmological constant,
+// and +Inf for a universe that expands forever without accelerating.
+// It is NaN for a universe that recollapses, where E(z) is NaN at the turning point.
+// The numerical integration is set by the Integration of cos.
+//   Rindler, 1956, MNRAS, 116, 662
+func EventHorizon(cos FLRW, z float64) (distanceMpc float64) {
+	return cos.HubbleDistance() * eventIntegral(cos, z)
+}
+
+// HubbleSphereRadius is the proper radius of the Hubble sphere at z,
+//   R_H = c / H(z) = D_H / E(z)
+// beyond which objects recede faster than the speed of light.
+func HubbleSphereRadius(cos FLRW, z float64) (distanceMpc float64) {
+	return cos.HubbleDistance() / cos.E(z)
+}
+
+// conformalIntegral is
+//   Integral_z^∞ dz'/E(z') = Integral_0^a da'/(a'^2 E(a'))
+// integrated in s = sqrt(a), in which the integrand is smooth
+// for both matter and radiation domination,
+//   Integral_0^sqrt(a) 2 ds / (s^3 E(s))
+func conformalIntegral(cos FLRW, z float64) float64 {
+	// E ~ (1+z)^q at high z, where the integral converges for q > 1.
+	switch q := expansionExponent(cos, 1e6, 1e7); {
+	case math.IsNaN(q):
+		return math.NaN()
+	case !(q > 1):
+		return math.Inf(1)
+	}
+	integrand := func(s float64) float64 {
+		// The integrand is finite as s -> 0,
+		// but s^3 E(s) underflows to 0 * Inf there.
+		// The interval [0, 1e-50] contributes nothing.
+		if s < 1e-50 {
+			return 0
+		}
+		return 2 / (s * s * s * cos.E(1/(s*s)-1))
+	}
+	return integrationOf(cos).integrate(integrand, 0, math.Sqrt(1/(1+z)))
+}
+
+// eventIntegral is
+//   Integral_{-1}^z dz'/E(z') = Integral_0^{1+z} dx/E(x-1)
+func eventIntegral(cos FLRW, z float64) float64 {
+	// E ~ (1+z)^q as z -> -1, where the integral converges for q < 1.
+	// E is +Inf for dark energy that grows faster than any power of 1+z.
+	switch q := expansionExponent(cos, 1e-6, 1e-7); {
+	case math.IsInf(cos.E(1e-7-1), 1):
+	case math.IsNaN(q):
+		return math.NaN()
+	case !(q < 1):
+		return math.Inf(1)
+	}
+	integrand := func(x float64) float64 { return cos.Einv(x - 1) }
+	return integrationOf(cos).integrate(integrand, 0, 1+z)
+}
+
+// expansionExponent is the logarithmic slope d ln E / d ln(1+z)
+// between 1+z = x1 and 1+z = x2.
+func expansionExponent(cos FLRW, x1, x2 float64) float64 {
+	return math.Log(cos.E(x1-1)/cos.E(x2-1)) / math.Log(x1/x2)
+}
@@ -0,0 +1,67 @@
+package cosmo
+
+import (
+	"math"
+	"testing"
+)
+
+var zHorizon = []float64{0, 0.5, 1, 3, 10, 1100}
+
+// Einstein-de Sitter: D_p = 2 D_H / sqrt(1+z), and no event horizon
+func TestHorizonEdS(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 1}
+	for _, z := range zHorizon {
+		exp := 2 * cos.HubbleDistance() / math.Sqrt(1+z)
+		runTest(func(z float64) float64 { return ParticleHorizon(cos, z) }, z, exp, distTol, t, 0)
+		exp = 2 * hubbleTime(cos.H0) / math.Sqrt(1+z)
+		runTest(func(z float64) float64 { return ConformalTime(cos, z) }, z, exp, 1e-9, t, 0)
+		runTest(func(z float64) float64 { return EventHorizon(cos, z) }, z, math.Inf(1), 0, t, 0)
+	}
+}
+
+// de Sitter: D_e = D_H (1+z), and no particle horizon
+func TestHorizonDeSitter(t *testing.T) {
+	cos := LambdaCDM{H0: 70, Om0: 0, Ol0: 1}
+	for _, z := range zHorizon {
+		exp := cos.HubbleDistance() * (1 + z)
+		runTest(func(z float64) float64 { return EventHorizon(cos, z) }, z, exp, distTol, t, 0)
+		runTest(func(z float64) float64 { return ParticleHorizon(cos, z) }, z, math.Inf(1), 0, t, 0)
+		runTest(func(z float64) float64 { return HubbleSphereRadius(cos, z) }, z, cos.HubbleDistance(), distTol, t, 0)
+	}
+}
+
+// The horizons differ from their value today by the comoving distance.
+func TestHorizonComovingDistance(t *testing.T) {
+	for name, cos := range inverseCosmologies {
+		dp0 := ParticleHorizon(cos, 0)
+		de0 := EventHorizon(cos, 0)
+		for _, z := range zHorizon {
+			dc := cos.ComovingDistance(z)
+			if dp := ParticleHorizon(cos, z); !(math.Abs(dp0-dp-dc) <= 1e-7*dp0) {
+				t.Errorf("%s ParticleHorizon(%v): expected %v, got %v", name, z, dp0-dc, dp)
+			}
+			if de := EventHorizon(cos, z); !(math.Abs(de-de0-dc) <= 1e-7*de) {
+				t.Errorf("%s EventHorizon(%v): expected %v, got %v", name, z, de0+dc, de)
+			}
+			// c [Mpc/Gyr] = D_H / t_H
+			exp := ParticleHorizon(cos, z) * hubbleTime(70) / cos.HubbleDistance()
+			runTest(func(z float64) float64 { return ConformalTime(cos, z) }, z, exp, 1e-9*exp, t, 0)
+			runTest(func(z float64) float64 { return HubbleSphereRadius(cos, z) }, z, cos.HubbleDistance()*cos.Einv(z), 1e-12, t, 0)
+		}
+	}
+}
+
+func TestHorizonNoAcceleration(t *testing.T) {
+	for _, cos := range []FLRW{
+		// Open, expands forever without accelerating
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0},
+		WCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.3},
+	} {
+		runTest(func(z float64) float64 { return EventHorizon(cos, z) }, 0, math.Inf(1), 0, t, 0)
+	}
+	// Recollapses
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: -0.2}
+	if de := EventHorizon(cos, 0); !math.IsNaN(de) {
+		t.Errorf("%v EventHorizon(0): expected NaN, got %v", cos, de)
+	}
+}
@@ -0,0 +1,335 @@
+package cosmo
+
+import (
+	"gonum.org/v1/gonum/integrate/quad"
+	"math"
+)
+
+// IntegrationMethod selects the numerical integration scheme of an Integration.
+type IntegrationMethod int
+
+const (
+	// IntegrateFixed is fixed Gauss-Legendre quadrature, with 1000 points by default.
+	IntegrateFixed IntegrationMethod = iota
+	// IntegrateGaussKronrod is adaptive 7-15 point Gauss-Kronrod quadrature,
+	// which bisects the interval with the largest error estimate until the tolerance is met.
+	//   Piessens et al., 1983, QUADPACK, Springer.  QAG with the 15-point rule
+	IntegrateGaussKronrod
+	// IntegrateTanhSinh is tanh-sinh (double exponential) quadrature,
+	// which halves its step until successive estimates agree to the tolerance.
+	//   Takahasi & Mori, 1974, Publ. RIMS, Kyoto Univ., 9, 721
+	IntegrateTanhSinh
+	// IntegrateRomberg is Romberg integration, Richardson extrapolation of the trapezoidal rule.
+	// It suits smooth integrands on finite intervals
+	// and converges slowly for the age integrals out to z = ∞.
+	IntegrateRomberg
+)
+
+const (
+	defaultIntegrationPoints  = 1000
+	defaultIntegrationRelTol  = 1e-10
+	defaultIntegrationMaxEval = 100000
+)
+
+// Integration configures the numerical integration of the distances and times
+// of the types that do not have an analytic form for them.
+// Each type has an Integration field.
+// The zero value is the 1000-point Gauss-Legendre quadrature
+// that all types used before Integration was introduced.
+//
+// The adaptive methods stop when the estimated error is below max(AbsTol, RelTol |result|)
+// or after about MaxEval evaluations of the integrand, whichever comes first.
+// For IntegrateFixed, MaxEval is the number of points and the tolerances are ignored.
+// The Validate method of each type checks its Integration along with the other parameters.
+type Integration struct {
+	Method  IntegrationMethod
+	AbsTol  float64 // Absolute tolerance.  Default 0
+	RelTol  float64 // Relative tolerance.  Default 1e-10 if AbsTol is also 0
+	MaxEval int     // Maximum number of integrand evaluations.  Default 100000; 1000 for IntegrateFixed
+}
+
+// integrationOf is the Integration of cos,
+// or the default one for an FLRW defined outside of this package.
+func integrationOf(cos FLRW) Integration {
+	if c, ok := cos.(interface{ integration() Integration }); ok {
+		return c.integration()
+	}
+	return Integration{}
+}
+
+// ComovingDistanceWithError is the comoving distance to z
+// and an estimate of the error of its numerical integration,
+// with the Integration of cos.
+// It integrates even for cosmologies where cos.ComovingDistance has an analytic form.
+func ComovingDistanceWithError(cos FLRW, z float64) (distanceMpc, errorMpc float64) {
+	I, err := integrationOf(cos).integrateWithError(cos.Einv, 0, z)
+	hubbleDistance := cos.HubbleDistance()
+	return hubbleDistance * I, hubbleDistance * err
+}
+
+// LookbackTimeWithError is the time from redshift 0 to z
+// and an estimate of the error of its numerical integration,
+// with the Integration of cos.
+// It integrates even for cosmologies where cos.LookbackTime has an analytic form.
+func LookbackTimeWithError(cos FLRW, z float64) (timeGyr, errorGyr float64) {
+	integrand := func(z float64) float64 { return cos.Einv(z) / (1 + z) }
+	I, err := integrationOf(cos).integrateWithError(integrand, 0, z)
+	hubbleTime := hubbleTime(SpeedOfLightKmS / cos.HubbleDistance())
+	return hubbleTime * I, hubbleTime * err
+}
+
+// AgeWithError is the time from redshift ∞ to z
+// and an estimate of the error of its numerical integration,
+// with the Integration of cos.
+// It integrates even for cosmologies where cos.Age has an analytic form.
+func AgeWithError(cos FLRW, z float64) (timeGyr, errorGyr float64) {
+	integrand := func(z float64) float64 { return cos.Einv(z) / (1 + z) }
+	I, err := integrationOf(cos).integrateWithError(integrand, z, math.Inf(1))
+	hubbleTime := hubbleTime(SpeedOfLightKmS / cos.HubbleDistance())
+	return hubbleTime * I, hubbleTime * err
+}
+
+// integrate is the integral of f from a to b.
+// One of a and b can be +Inf, and b < a gives minus the integral from b to a.
+func (in Integration) integrate(f func(float64) float64, a, b float64) float64 {
+	if in.Method == IntegrateFixed {
+		return in.fixed(f, a, b, in.points())
+	}
+	I, _ := in.integrateWithError(f, a, b)
+	return I
+}
+
+// integrateWithError is the integral of f from a to b, as integrate,
+// and an estimate of its absolute error.
+// For IntegrateFixed this doubles the cost: the estimate is the difference from half the points.
+func (in Integration) integrateWithError(f func(float64) float64, a, b float64) (I, errEstimate float64) {
+	switch {
+	case a == b:
+		return 0, 0
+	case b < a:
+		I, errEstimate = in.integrateWithError(f, b, a)
+		return -I, errEstimate
+	case in.Method == IntegrateFixed:
+		n := in.points()
+		I = in.fixed(f, a, b, n)
+		// With a single point, the estimate compares it to itself and is 0.
+		half := n / 2
+		if half < 1 {
+			half = 1
+		}
+		return I, math.Abs(I - in.fixed(f, a, b, half))
+	}
+
+	if math.IsInf(b, 1) {
+		// x = a + t/(1-t) maps t in [0, 1] to x in [a, ∞).
+		g, x0 := f, a
+		f = func(t float64) float64 {
+			if t >= 1 {
+				return 0
+			}
+			return g(x0+t/(1-t)) / ((1 - t) * (1 - t))
+		}
+		a, b = 0, 1
+	}
+	absTol, relTol, maxEval := in.AbsTol, in.RelTol, in.MaxEval
+	if (absTol == 0) && (relTol == 0) {
+		relTol = defaultIntegrationRelTol
+	}
+	if maxEval <= 0 {
+		maxEval = defaultIntegrationMaxEval
+	}
+	tol := func(I float64) float64 { return math.Max(absTol, relTol*math.Abs(I)) }
+
+	switch in.Method {
+	case IntegrateGaussKronrod:
+		return gaussKronrod(f, a, b, tol, maxEval)
+	case IntegrateTanhSinh:
+		return tanhSinh(f, a, b, tol, maxEval)
+	case IntegrateRomberg:
+		return romberg(f, a, b, tol, maxEval)
+	default:
+		return math.NaN(), math.NaN()
+	}
+}
+
+// validate checks that in is a known method with finite, non-negative tolerances
+// and a non-negative MaxEval.
+// The error, if any, is a *ParameterError for the cosmology that in belongs to.
+func (in Integration) validate(cosmology string) error {
+	switch {
+	case (in.Method < IntegrateFixed) || (in.Method > IntegrateRomberg):
+		return &ParameterError{Cosmology: cosmology, Parameter: "Integration.Method", Value: float64(in.Method),
+			Err: ErrInconsistent, Detail: "unknown IntegrationMethod"}
+	case !isFinite(in.AbsTol):
+		return &ParameterError{Cosmology: cosmology, Parameter: "Integration.AbsTol", Value: in.AbsTol, Err: ErrNotFinite}
+	case in.AbsTol < 0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "Integration.AbsTol", Value: in.AbsTol, Err: ErrNegative}
+	case !isFinite(in.RelTol):
+		return &ParameterError{Cosmology: cosmology, Parameter: "Integration.RelTol", Value: in.RelTol, Err: ErrNotFinite}
+	case in.RelTol < 0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "Integration.RelTol", Value: in.RelTol, Err: ErrNegative}
+	case in.MaxEval < 0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "Integration.MaxEval", Value: float64(in.MaxEval),
+			Err: ErrNegative, Detail: "0 selects the default"}
+	}
+	return nil
+}
+
+// points is the number of points of the fixed quadrature
+func (in Integration) points() int {
+	if in.MaxEval > 0 {
+		return in.MaxEval
+	}
+	return defaultIntegrationPoints
+}
+
+// fixed is n-point Gauss-Legendre quadrature of f from a to b.
+func (in Integration) fixed(f func(float64) float64, a, b float64, n int) float64 {
+	if b < a {
+		return -in.fixed(f, b, a, n)
+	}
+	// When given math.Inf(), quad.Fixed automatically redefines variables
+	// to successfully do the numerical integration.
+	return quad.Fixed(f, a, b, n, nil, 0)
+}
+
+// Nodes and weights of the 15-point Kronrod rule on [-1, 1]
+// and of the 7-point Gauss rule at its odd-indexed nodes.
+//   Piessens et al., 1983, QUADPACK, Springer.  QK15
+var (
+	kronrodNodes = [8]float64{
+		0.991455371120812639206854697526329, 0.949107912342758524526189684047851,
+		0.864864423359769072789712788640926, 0.741531185599394439863864773280788,
+		0.586087235467691130294144845693013, 0.405845151377397166906606412076961,
+		0.207784955007898467600689403773245, 0,
+	}
+	kronrodWeights = [8]float64{
+		0.022935322010529224963732008058970, 0.063092092629978553290700663189204,
+		0.104790010322250183839876322541518, 0.140653259715525918745189590510238,
+		0.169004726639267902826583426598550, 0.190350578064785409913256402421014,
+		0.204432940075298892414161999234649, 0.209482141084727828012999174891714,
+	}
+	gaussWeights = [4]float64{
+		0.129484966168869693270611432679082, 0.279705391489276667901467771423780,
+		0.381830050505118944950369775488975, 0.417959183673469387755102040816327,
+	}
+)
+
+// gaussKronrod15 is the 15-point Kronrod estimate of the integral of f from a to b
+// and its difference from the 7-point Gauss estimate.
+func gaussKronrod15(f func(float64) float64, a, b float64) (I, errEstimate float64) {
+	center := (a + b) / 2
+	halfLength := (b - a) / 2
+	fc := f(center)
+	kronrod := kronrodWeights[7] * fc
+	gauss := gaussWeights[3] * fc
+	for j := 0; j < 7; j++ {
+		dx := halfLength * kronrodNodes[j]
+		fsum := f(center-dx) + f(center+dx)
+		kronrod += kronrodWeights[j] * fsum
+		if j%2 == 1 {
+			gauss += gaussWeights[j/2] * fsum
+		}
+	}
+	return kronrod * halfLength, math.Abs((kronrod - gauss) * halfLength)
+}
+
+// gaussKronrod integrates f from a to b by repeatedly bisecting the subinterval
+// with the largest error estimate.
+func gaussKronrod(f func(float64) float64, a, b float64, tol func(float64) float64, maxEval int) (I, errEstimate float64) {
+	type interval struct {
+		a, b, I, err float64
+	}
+	I, errEstimate = gaussKronrod15(f, a, b)
+	intervals := []interval{{a, b, I, errEstimate}}
+	for nEval := 15; (errEstimate > tol(I)) && (nEval+30 <= maxEval); nEval += 30 {
+		worst := 0
+		for i := range intervals {
+			if intervals[i].err > intervals[worst].err {
+				worst = i
+			}
+		}
+		w := intervals[worst]
+		mid := (w.a + w.b) / 2
+		I1, err1 := gaussKronrod15(f, w.a, mid)
+		I2, err2 := gaussKronrod15(f, mid, w.b)
+		intervals[worst] = interval{w.a, mid, I1, err1}
+		intervals = append(intervals, interval{mid, w.b, I2, err2})
+		I, errEstimate = 0, 0
+		for _, s := range intervals {
+			I += s.I
+			errEstimate += s.err
+		}
+	}
+	return I, errEstimate
+}
+
+// tanhSinh integrates f from a to b with the substitution
+//   x = (a+b)/2 + (b-a)/2 tanh(pi/2 sinh t)
+// and the trapezoidal rule in t, halving the step in t until successive estimates agree.
+// The points are computed as offsets from the nearer end of the interval,
+// so that they never reach the end itself.
+func tanhSinh(f func(float64) float64, a, b float64, tol func(float64) float64, maxEval int) (I, errEstimate float64) {
+	const tMax = 3.5 // The weights are < 1e-20 beyond this
+	halfLength := (b - a) / 2
+	// term is the sum of the contributions at t and -t.
+	term := func(t float64) float64 {
+		u := math.Pi / 2 * math.Sinh(t)
+		coshU := math.Cosh(u)
+		weight := math.Pi / 2 * math.Cosh(t) / (coshU * coshU)
+		offset := halfLength / (math.Exp(u) * coshU) // (b-a)/2 (1 - tanh(u))
+		return weight * (f(a+offset) + f(b-offset))
+	}
+
+	h := 1.0
+	sum := math.Pi / 2 * f((a+b)/2)
+	for t := h; t <= tMax; t += h {
+		sum += term(t)
+	}
+	nEval := 1 + 2*int(tMax/h)
+	I = h * halfLength * sum
+	errEstimate = math.Inf(1)
+	for (errEstimate > tol(I)) && (nEval+2*int(tMax/h) <= maxEval) {
+		h /= 2
+		for t := h; t <= tMax; t += 2 * h {
+			sum += term(t)
+		}
+		nEval += 2 * int(tMax/(2*h))
+		prev := I
+		I = h * halfLength * sum
+		errEstimate = math.Abs(I - prev)
+	}
+	return I, errEstimate
+}
+
+// romberg integrates f from a to b with Richardson extrapolation
+// of the trapezoidal rule with 2^k intervals.
+func romberg(f func(float64) float64, a, b float64, tol func(float64) float64, maxEval int) (I, errEstimate float64) {
+	h := b - a
+	row := []float64{h / 2 * (f(a) + f(b))}
+	nEval := 2
+	I, errEstimate = row[0], math.Inf(1)
+	for k := 1; (errEstimate > tol(I)) && (nEval+(1<<(k-1)) <= maxEval); k++ {
+		nNew := 1 << (k - 1)
+		var sum float64
+		for i := 0; i < nNew; i++ {
+			sum += f(a + (float64(i)+0.5)*h)
+		}
+		nEval += nNew
+		h /= 2
+		next := make([]float64, k+1)
+		next[0] = row[0]/2 + h*sum
+		factor := 1.0
+		for j := 1; j <= k; j++ {
+			factor *= 4
+			next[j] = next[j-1] + (next[j-1]-row[j-1])/(factor-1)
+		}
+		// Require at least 5 levels, so that agreement is not by chance.
+		if k >= 4 {
+			errEstimate = math.Abs(next[k] - row[k-1])
+		}
+		row = next
+		I = row[k]
+	}
+	return I, errEstimate
+}
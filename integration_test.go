@@ -0,0 +1,150 @@
+package cosmo
+
+import (
+	"math"
+	"testing"
+)
+
+var integrationMethods = map[string]IntegrationMethod{
+	"Fixed":        IntegrateFixed,
+	"GaussKronrod": IntegrateGaussKronrod,
+	"TanhSinh":     IntegrateTanhSinh,
+	"Romberg":      IntegrateRomberg,
+}
+
+func TestIntegrate(t *testing.T) {
+	for name, method := range integrationMethods {
+		in := Integration{Method: method}
+		for _, tc := range []struct {
+			f    func(float64) float64
+			a, b float64
+			exp  float64
+		}{
+			{math.Exp, 0, 1, math.E - 1},
+			{math.Exp, 1, 0, 1 - math.E},
+			{math.Sin, 0, math.Pi, 2},
+			{func(x float64) float64 { return 1 / (1 + x*x) }, -1, 3, math.Atan(3) + math.Pi/4},
+			{math.Exp, 0.5, 0.5, 0},
+		} {
+			I, err := in.integrateWithError(tc.f, tc.a, tc.b)
+			if !(math.Abs(I-tc.exp) <= 1e-9) {
+				t.Errorf("%s: integral from %v to %v: expected %v, got %v", name, tc.a, tc.b, tc.exp, I)
+			}
+			if !(err <= 1e-6) {
+				t.Errorf("%s: integral from %v to %v: expected a small error estimate, got %v", name, tc.a, tc.b, err)
+			}
+			runTest(func(float64) float64 { return in.integrate(tc.f, tc.a, tc.b) }, 0, I, 0, t, 0)
+		}
+	}
+}
+
+// Integral_0^∞ dx / (1+x)^3 = 1/2
+func TestIntegrateInfinite(t *testing.T) {
+	f := func(x float64) float64 { return 1 / ((1 + x) * (1 + x) * (1 + x)) }
+	for name, method := range integrationMethods {
+		in := Integration{Method: method}
+		if I := in.integrate(f, 0, math.Inf(1)); !(math.Abs(I-0.5) <= 1e-9) {
+			t.Errorf("%s: expected 0.5, got %v", name, I)
+		}
+	}
+}
+
+func TestIntegrateMaxEval(t *testing.T) {
+	for name, method := range integrationMethods {
+		if method == IntegrateFixed {
+			continue
+		}
+		in := Integration{Method: method, RelTol: 1e-15, MaxEval: 200}
+		nEval := 0
+		f := func(x float64) float64 {
+			nEval++
+			return math.Sqrt(x)
+		}
+		I, err := in.integrateWithError(f, 0, 1)
+		if nEval > in.MaxEval {
+			t.Errorf("%s: expected at most %d evaluations, got %d", name, in.MaxEval, nEval)
+		}
+		if !(math.Abs(I-2./3) <= err) && !(math.Abs(I-2./3) <= 1e-15) {
+			t.Errorf("%s: error estimate %v is less than the actual error %v", name, err, math.Abs(I-2./3))
+		}
+	}
+}
+
+// A single fixed point gives an estimate, and an error estimate of 0, rather than a panic.
+func TestIntegrateFixedOnePoint(t *testing.T) {
+	cos := WCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, Integration: Integration{MaxEval: 1}}
+	for name, f := range map[string]func(FLRW, float64) (float64, float64){
+		"ComovingDistance": ComovingDistanceWithError,
+		"LookbackTime":     LookbackTimeWithError,
+		"Age":              AgeWithError,
+	} {
+		if v, err := f(cos, 1); !(v > 0) || (err != 0) {
+			t.Errorf("%sWithError(1): expected a positive value with error 0, got %v +/- %v", name, v, err)
+		}
+	}
+}
+
+func integrationCosmologies(in Integration) map[string]FLRW {
+	return map[string]FLRW{
+		"LambdaCDMClosedRadiation": LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9, Tcmb0: 2.725, Neff: 3.04, Integration: in},
+		"WACDM":                    WACDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, WA: 0.2, Integration: in},
+		"WzCDM":                    WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: wConst(-0.9), Integration: in},
+	}
+}
+
+// The distances and times of every method agree with a tight adaptive integration.
+// At z=1100 the age from the default fixed quadrature is only accurate to ~ 1e-5,
+// which its error estimate reflects.
+func TestIntegrationCosmology(t *testing.T) {
+	references := integrationCosmologies(Integration{Method: IntegrateGaussKronrod, RelTol: 1e-13})
+	for name, method := range integrationMethods {
+		for cname, cos := range integrationCosmologies(Integration{Method: method}) {
+			ref := references[cname]
+			for _, z := range []float64{0.01, 1, 1100} {
+				d := ref.ComovingDistance(z)
+				runTest(cos.ComovingDistance, z, d, 1e-8*d, t, 0)
+				tl := ref.LookbackTime(z)
+				runTest(cos.LookbackTime, z, tl, 1e-8*tl, t, 0)
+			}
+			if method == IntegrateRomberg {
+				// The age integrand goes as (1-t)^(1/2) at the end of the mapped interval.
+				continue
+			}
+			for _, z := range []float64{0, 1100} {
+				exp := ref.Age(z)
+				obs, err := AgeWithError(cos, z)
+				runTest(cos.Age, z, obs, 1e-12*obs, t, 0)
+				if !(math.Abs(obs-exp) <= math.Max(1e-8*exp, err)) {
+					t.Errorf("%s %s Age(%v): expected %v, got %v +/- %v", name, cname, z, exp, obs, err)
+				}
+			}
+		}
+	}
+}
+
+func TestWithError(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3, Integration: Integration{Method: IntegrateGaussKronrod}}
+	for _, z := range []float64{0.5, 3} {
+		for fname, f := range map[string]struct {
+			withError func(FLRW, float64) (float64, float64)
+			exact     func(float64) float64
+		}{
+			"ComovingDistance": {ComovingDistanceWithError, cos.ComovingDistance},
+			"LookbackTime":     {LookbackTimeWithError, cos.LookbackTime},
+			"Age":              {AgeWithError, cos.Age},
+		} {
+			obs, err := f.withError(cos, z)
+			exp := f.exact(z)
+			if !(err <= 1e-9*exp) || !(math.Abs(obs-exp) <= math.Max(err, 1e-10*exp)) {
+				t.Errorf("%sWithError(%v): expected %v, got %v +/- %v", fname, z, exp, obs, err)
+			}
+		}
+	}
+}
+
+func BenchmarkLambdaCDMComovingDistanceGaussKronrod(b *testing.B) {
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.6, Integration: Integration{Method: IntegrateGaussKronrod}}
+	for i := 0; i < b.N; i++ {
+		cos.comovingDistanceZ1Z2Integrate(0, 1.0)
+	}
+}
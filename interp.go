@@ -0,0 +1,102 @@
+package cosmo
+
+import (
+	"sort"
+)
+
+// interpolator is a one-dimensional interpolation scheme through (x, y) points.
+// Implementations assume that x is strictly increasing
+// and only need to be valid for x within the range of the points.
+type interpolator interface {
+	at(x float64) float64
+}
+
+// linearInterpolator interpolates linearly between points.
+type linearInterpolator struct {
+	x, y []float64
+}
+
+func newLinearInterpolator(x, y []float64) linearInterpolator {
+	return linearInterpolator{x: x, y: y}
+}
+
+func (li linearInterpolator) at(x float64) float64 {
+	i := segment(li.x, x)
+	t := (x - li.x[i]) / (li.x[i+1] - li.x[i])
+	return li.y[i] + t*(li.y[i+1]-li.y[i])
+}
+
+// cubicSpline is a natural cubic spline:
+// twice-differentiable, with zero second derivative at both ends.
+type cubicSpline struct {
+	x, y []float64
+	y2   []float64 // second derivatives at x
+}
+
+// newCubicSpline solves the tridiagonal system for the second derivatives
+// of a natural cubic spline.
+//   Press et al., Numerical Recipes, 3rd ed., Section 3.3
+func newCubicSpline(x, y []float64) cubicSpline {
+	n := len(x)
+	y2 := make([]float64, n)
+	u := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		sig := (x[i] - x[i-1]) / (x[i+1] - x[i-1])
+		p := sig*y2[i-1] + 2
+		y2[i] = (sig - 1) / p
+		u[i] = (y[i+1]-y[i])/(x[i+1]-x[i]) - (y[i]-y[i-1])/(x[i]-x[i-1])
+		u[i] = (6*u[i]/(x[i+1]-x[i-1]) - sig*u[i-1]) / p
+	}
+	y2[n-1] = 0
+	for k := n - 2; k >= 0; k-- {
+		y2[k] = y2[k]*y2[k+1] + u[k]
+	}
+	return cubicSpline{x: x, y: y, y2: y2}
+}
+
+func (cs cubicSpline) at(x float64) float64 {
+	i := segment(cs.x, x)
+	h := cs.x[i+1] - cs.x[i]
+	a := (cs.x[i+1] - x) / h
+	b := (x - cs.x[i]) / h
+	return a*cs.y[i] + b*cs.y[i+1] +
+		((a*a*a-a)*cs.y2[i]+(b*b*b-b)*cs.y2[i+1])*(h*h)/6
+}
+
+// cubicHermite is a piecewise cubic through points with given derivatives,
+// which is accurate to O(h^4) without the end conditions of a spline.
+type cubicHermite struct {
+	x, y []float64
+	dydx []float64
+}
+
+func newCubicHermite(x, y, dydx []float64) cubicHermite {
+	return cubicHermite{x: x, y: y, dydx: dydx}
+}
+
+func (ch cubicHermite) at(x float64) float64 {
+	return ch.atSegment(segment(ch.x, x), x)
+}
+
+// atSegment is at(x) for x in the interval [ch.x[i], ch.x[i+1]],
+// for callers that can find i without a search.
+func (ch cubicHermite) atSegment(i int, x float64) float64 {
+	h := ch.x[i+1] - ch.x[i]
+	t := (x - ch.x[i]) / h
+	t2, t3 := t*t, t*t*t
+	return (2*t3-3*t2+1)*ch.y[i] + (t3-2*t2+t)*h*ch.dydx[i] +
+		(-2*t3+3*t2)*ch.y[i+1] + (t3-t2)*h*ch.dydx[i+1]
+}
+
+// segment is the index i of the interval [xs[i], xs[i+1]] that contains x.
+// Values outside the range are assigned to the first or last interval.
+func segment(xs []float64, x float64) int {
+	i := sort.SearchFloat64s(xs, x) - 1
+	switch {
+	case i < 0:
+		return 0
+	case i > len(xs)-2:
+		return len(xs) - 2
+	}
+	return i
+}
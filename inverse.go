@@ -0,0 +1,261 @@
+package cosmo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoRedshift is returned by the ZAt* functions
+// when no redshift gives the requested distance or time.
+var ErrNoRedshift = errors.New("cosmo: no redshift for value")
+
+const (
+	// defaultZTol is the tolerance in z used by the ZAt* functions for tol <= 0.
+	defaultZTol = 1e-10
+	// zSearchMax is the highest redshift searched by the ZAt* functions.
+	zSearchMax = 1e6
+)
+
+// ZAtComovingDistance is the redshift at which cos.ComovingDistance is distanceMpc.
+//   tol : Absolute tolerance in z.  tol <= 0 selects 1e-10.
+func ZAtComovingDistance(cos FLRW, distanceMpc, tol float64) (z float64, err error) {
+	return zAtIncreasing(cos, cos.ComovingDistance, distanceMpc, tol, "comoving distance")
+}
+
+// ZAtLuminosityDistance is the redshift at which cos.LuminosityDistance is distanceMpc.
+//   tol : Absolute tolerance in z.  tol <= 0 selects 1e-10.
+func ZAtLuminosityDistance(cos FLRW, distanceMpc, tol float64) (z float64, err error) {
+	return zAtIncreasing(cos, cos.LuminosityDistance, distanceMpc, tol, "luminosity distance")
+}
+
+// ZAtDistanceModulus is the redshift at which cos.DistanceModulus is distanceModulusMag.
+//   tol : Absolute tolerance in z.  tol <= 0 selects 1e-10.
+func ZAtDistanceModulus(cos FLRW, distanceModulusMag, tol float64) (z float64, err error) {
+	distanceMpc := math.Pow(10, (distanceModulusMag-25)/5)
+	return ZAtLuminosityDistance(cos, distanceMpc, tol)
+}
+
+// ZAtAngularDiameterDistance is the pair of redshifts at which
+// cos.AngularDiameterDistance is distanceMpcRad.
+//   tol : Absolute tolerance in z.  tol <= 0 selects 1e-10.
+//
+// The angular diameter distance reaches a maximum, at z ~ 1.6 for
+// the concordance cosmology, and decreases beyond it,
+// so a distance below the maximum is reached twice.
+// zLow is on the rising branch below the maximum
+// and zHigh on the falling branch above it.
+// zHigh is NaN if the angular diameter distance has no maximum below z = 1e6,
+// e.g., for a universe with no matter.
+// The error wraps ErrNoRedshift if distanceMpcRad exceeds the maximum.
+func ZAtAngularDiameterDistance(cos FLRW, distanceMpcRad, tol float64) (zLow, zHigh float64, err error) {
+	if tol <= 0 {
+		tol = defaultZTol
+	}
+	zPeak, dPeak, found := angularDiameterDistanceMax(cos, tol)
+	if !found {
+		zLow, err = zAtIncreasing(cos, cos.AngularDiameterDistance, distanceMpcRad, tol, "angular diameter distance")
+		return zLow, math.NaN(), err
+	}
+	if !(distanceMpcRad <= dPeak) || (distanceMpcRad < 0) {
+		return math.NaN(), math.NaN(),
+			fmt.Errorf("%w: angular diameter distance %v Mpc is outside [0, %v] for %v",
+				ErrNoRedshift, distanceMpcRad, dPeak, cos)
+	}
+
+	f := func(z float64) float64 { return cos.AngularDiameterDistance(z) - distanceMpcRad }
+	zLow, err = brent(f, 0, zPeak, tol)
+	if err != nil {
+		return math.NaN(), math.NaN(), err
+	}
+	// Search out along the falling branch for a bracket.
+	hi := 2 * (1 + zPeak)
+	for ; f(hi) > 0; hi *= 2 {
+		if hi > zSearchMax {
+			return zLow, math.NaN(), nil
+		}
+	}
+	zHigh, err = brent(f, zPeak, hi, tol)
+	if err != nil {
+		return zLow, math.NaN(), err
+	}
+	return zLow, zHigh, nil
+}
+
+// angularDiameterDistanceMax finds the redshift and value of
+// the maximum angular diameter distance.
+func angularDiameterDistanceMax(cos FLRW, tol float64) (zPeak, dPeak float64, found bool) {
+	f := cos.AngularDiameterDistance
+	// Step out in z until the angular diameter distance turns over.
+	lo, mid := 0.0, 1.0
+	fMid := f(mid)
+	for {
+		hi := 2 * mid
+		if hi > zSearchMax {
+			return math.NaN(), math.NaN(), false
+		}
+		fHi := f(hi)
+		if math.IsNaN(fHi) {
+			return math.NaN(), math.NaN(), false
+		}
+		if fHi < fMid {
+			zPeak, dPeak = goldenMax(f, lo, hi, tol)
+			return zPeak, dPeak, true
+		}
+		lo, mid, fMid = mid, hi, fHi
+	}
+}
+
+// zAtIncreasing finds z such that f(z) = target
+// for f increasing from f(0) = 0.
+//
+// If f is NaN at some z, the model has a maximum reachable redshift
+// and the search is limited to below it.
+func zAtIncreasing(cos FLRW, f func(float64) float64, target, tol float64, name string) (z float64, err error) {
+	if tol <= 0 {
+		tol = defaultZTol
+	}
+	switch {
+	case target == 0:
+		return 0, nil
+	case !(target > 0) || math.IsInf(target, 1):
+		return math.NaN(), fmt.Errorf("%w: %s %v", ErrNoRedshift, name, target)
+	}
+
+	g := func(z float64) float64 { return f(z) - target }
+	lo, hi := 0.0, 1.0
+	for {
+		gHi := g(hi)
+		if math.IsNaN(gHi) {
+			// Integrating up to just past the maximum reachable redshift
+			// need not give NaN, so stop exactly there.
+			zMax := AnalyzeExpansion(cos).ZMax
+			if !(zMax < hi) {
+				return math.NaN(), fmt.Errorf("%w: %s %v at z=%v", ErrNoRedshift, name, f(hi), hi)
+			}
+			if gMax := g(zMax); !(gMax >= 0) {
+				return math.NaN(), fmt.Errorf("%w: %s %v is beyond the maximum reachable redshift %v",
+					ErrNoRedshift, name, target, zMax)
+			}
+			return brent(g, 0, zMax, tol)
+		}
+		if gHi >= 0 {
+			return brent(g, lo, hi, tol)
+		}
+		if hi > zSearchMax {
+			return math.NaN(), fmt.Errorf("%w: %s %v is not reached by z=%v",
+				ErrNoRedshift, name, target, zSearchMax)
+		}
+		lo, hi = hi, 2*hi
+	}
+}
+
+// ageInverter is implemented by cosmologies for which Age has a closed-form inverse
+// for some parameters.
+type ageInverter interface {
+	// zAtAge returns ok=false if there is no closed form for these parameters.
+	zAtAge(timeGyr float64) (z float64, ok bool)
+}
+
+// ZAtAge is the redshift at which cos.Age is timeGyr.
+//   tol : Absolute tolerance in z.  tol <= 0 selects 1e-10.
+//
+// An age beyond the present age gives a future redshift, -1 < z < 0.
+// The analytic inverse is used where Age is analytic,
+// e.g., for FlatLCDM without radiation;
+// tol then does not apply.
+func ZAtAge(cos FLRW, timeGyr, tol float64) (z float64, err error) {
+	if !(timeGyr > 0) || math.IsInf(timeGyr, 1) {
+		return math.NaN(), fmt.Errorf("%w: age %v Gyr", ErrNoRedshift, timeGyr)
+	}
+	if ai, ok := cos.(ageInverter); ok {
+		if z, ok := ai.zAtAge(timeGyr); ok {
+			return z, nil
+		}
+	}
+	return zAtDecreasing(cos.Age, timeGyr, tol, "age")
+}
+
+// ZAtLookbackTime is the redshift at which cos.LookbackTime is timeGyr.
+//   tol : Absolute tolerance in z.  tol <= 0 selects 1e-10.
+//
+// A negative look-back time gives a future redshift, -1 < z < 0.
+// The analytic inverse of Age is used where available.
+func ZAtLookbackTime(cos FLRW, timeGyr, tol float64) (z float64, err error) {
+	if math.IsNaN(timeGyr) || math.IsInf(timeGyr, 0) {
+		return math.NaN(), fmt.Errorf("%w: look-back time %v Gyr", ErrNoRedshift, timeGyr)
+	}
+	if timeGyr == 0 {
+		return 0, nil
+	}
+	if ai, ok := cos.(ageInverter); ok {
+		// Probe whether there is a closed form for these parameters.
+		if _, ok := ai.zAtAge(1); ok {
+			age0 := cos.Age(0)
+			if !(timeGyr < age0) {
+				return math.NaN(), fmt.Errorf("%w: look-back time %v Gyr exceeds the age %v Gyr of %v",
+					ErrNoRedshift, timeGyr, age0, cos)
+			}
+			z, _ := ai.zAtAge(age0 - timeGyr)
+			return z, nil
+		}
+	}
+	if timeGyr < 0 {
+		// The integrated look-back times only run forwards from z=0,
+		// so go through the age for the future.
+		age0 := cos.Age(0)
+		sinceNow := func(z float64) float64 { return cos.Age(z) - age0 }
+		return zAtDecreasing(sinceNow, -timeGyr, tol, "look-back time")
+	}
+	negLookbackTime := func(z float64) float64 { return -cos.LookbackTime(z) }
+	return zAtDecreasing(negLookbackTime, -timeGyr, tol, "look-back time")
+}
+
+// zAtDecreasing finds z such that f(z) = target for f decreasing with z,
+// as Age is, searching into the future, -1 < z < 0, if target > f(0).
+func zAtDecreasing(f func(float64) float64, target, tol float64, name string) (z float64, err error) {
+	if tol <= 0 {
+		tol = defaultZTol
+	}
+	g := func(z float64) float64 { return f(z) - target }
+	g0 := g(0)
+	switch {
+	case g0 == 0:
+		return 0, nil
+	case math.IsNaN(g0):
+		return math.NaN(), fmt.Errorf("%w: %s is undefined at z=0", ErrNoRedshift, name)
+	case g0 > 0:
+		// Target is in the past
+		lo, hi := 0.0, 1.0
+		for {
+			gHi := g(hi)
+			switch {
+			case math.IsNaN(gHi):
+				return math.NaN(), fmt.Errorf("%w: %s is undefined at z=%v", ErrNoRedshift, name, hi)
+			case gHi <= 0:
+				return brent(g, lo, hi, tol)
+			case hi > zSearchMax:
+				return math.NaN(), fmt.Errorf("%w: %s %v is not reached by z=%v",
+					ErrNoRedshift, name, target, zSearchMax)
+			}
+			lo, hi = hi, 2*hi
+		}
+	default:
+		// Target is in the future.  Step out in ln(1+z).
+		lo, hi := 0.0, 0.0
+		for x := -1.0; ; x *= 2 {
+			lo = math.Expm1(x)
+			gLo := g(lo)
+			switch {
+			case math.IsNaN(gLo):
+				return math.NaN(), fmt.Errorf("%w: %s is undefined at z=%v", ErrNoRedshift, name, lo)
+			case gLo >= 0:
+				return brent(g, lo, hi, tol)
+			case x < -expansionScanLnOpzMax:
+				return math.NaN(), fmt.Errorf("%w: %s %v is not reached by z=%v",
+					ErrNoRedshift, name, target, lo)
+			}
+			hi = lo
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package cosmo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+const zTol = 1e-8
+
+var inverseCosmologies = map[string]FLRW{
+	"FlatLCDM":          FlatLCDM{H0: 70, Om0: 0.3},
+	"FlatLCDMRadiation": FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04},
+	"LambdaCDMOpen":     LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.5},
+	"LambdaCDMClosed":   LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9},
+	"WACDM":             WACDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, WA: 0.2},
+}
+
+var zInverse = []float64{0.01, 0.5, 1.0, 3.0, 10.0}
+
+func TestZAtDistance(t *testing.T) {
+	for name, cos := range inverseCosmologies {
+		for _, z := range zInverse {
+			for fname, f := range map[string]func(FLRW, float64, float64) (float64, error){
+				"ComovingDistance":   ZAtComovingDistance,
+				"LuminosityDistance": ZAtLuminosityDistance,
+				"DistanceModulus":    ZAtDistanceModulus,
+			} {
+				var d float64
+				switch fname {
+				case "ComovingDistance":
+					d = cos.ComovingDistance(z)
+				case "LuminosityDistance":
+					d = cos.LuminosityDistance(z)
+				case "DistanceModulus":
+					d = cos.DistanceModulus(z)
+				}
+				got, err := f(cos, d, 0)
+				if err != nil {
+					t.Errorf("%s ZAt%s(%v): unexpected error %v", name, fname, d, err)
+					continue
+				}
+				if math.Abs(got-z) > zTol*(1+z) {
+					t.Errorf("%s ZAt%s: expected z=%v, got %v", name, fname, z, got)
+				}
+			}
+		}
+	}
+}
+
+func TestZAtAngularDiameterDistance(t *testing.T) {
+	for name, cos := range inverseCosmologies {
+		// Pair each redshift below the maximum with one above it
+		for _, z := range []float64{0.5, 1.0} {
+			d := cos.AngularDiameterDistance(z)
+			zLow, zHigh, err := ZAtAngularDiameterDistance(cos, d, 0)
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", name, err)
+				continue
+			}
+			if math.Abs(zLow-z) > zTol {
+				t.Errorf("%s: expected zLow=%v, got %v", name, z, zLow)
+			}
+			if !(zHigh > zLow) {
+				t.Errorf("%s: expected zHigh > zLow=%v, got %v", name, zLow, zHigh)
+			}
+			if dHigh := cos.AngularDiameterDistance(zHigh); math.Abs(dHigh-d) > distTol {
+				t.Errorf("%s: expected D_A(zHigh=%v) = %v, got %v", name, zHigh, d, dHigh)
+			}
+		}
+	}
+}
+
+func TestZAtAngularDiameterDistanceMax(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	zPeak, dPeak, found := angularDiameterDistanceMax(cos, defaultZTol)
+	if !found {
+		t.Fatalf("Expected a maximum in the angular diameter distance")
+	}
+	// Calculated by golden-section maximization of D_A(z)
+	// with Simpson's rule integration of 1/E(z).
+	runTest(func(float64) float64 { return zPeak }, 0, 1.60542259, 1e-6, t, 0)
+	runTest(func(float64) float64 { return dPeak }, 0, 1747.57190088, distTol, t, 0)
+	runTest(cos.AngularDiameterDistance, zPeak, dPeak, distTol, t, 0)
+
+	zLow, zHigh, err := ZAtAngularDiameterDistance(cos, dPeak+1, 0)
+	if !errors.Is(err, ErrNoRedshift) {
+		t.Errorf("Expected ErrNoRedshift above the maximum, got %v, %v, %v", zLow, zHigh, err)
+	}
+}
+
+// A tolerance below the float spacing at the maximum still terminates.
+func TestZAtAngularDiameterDistanceTinyTol(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	for _, tol := range []float64{1e-20, 1e-300} {
+		zLow, zHigh, err := ZAtAngularDiameterDistance(cos, 1000, tol)
+		if err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+		runTest(cos.AngularDiameterDistance, zLow, 1000, distTol, t, 0)
+		runTest(cos.AngularDiameterDistance, zHigh, 1000, distTol, t, 0)
+	}
+}
+
+func TestBrentErrors(t *testing.T) {
+	// NaN inside the bracket
+	f := func(x float64) float64 {
+		if (0.2 < x) && (x < 0.8) {
+			return math.NaN()
+		}
+		return x - 0.5
+	}
+	if x, err := brent(f, 0, 1, 1e-10); !errors.Is(err, ErrNoRedshift) || !math.IsNaN(x) {
+		t.Errorf("Expected ErrNoRedshift for NaN inside the bracket, got %v, %v", x, err)
+	}
+	if _, err := brent(f, 0, 0.1, 1e-10); !errors.Is(err, errNotBracketed) {
+		t.Errorf("Expected errNotBracketed, got %v", err)
+	}
+}
+
+// In a universe with no matter the angular diameter distance
+//   D_A = c/H0 z / (1+z)
+// rises monotonically, so there is no high-redshift branch.
+func TestZAtAngularDiameterDistanceEmpty(t *testing.T) {
+	cos := LambdaCDM{H0: 70, Om0: 0, Ol0: 1}
+	zLow, zHigh, err := ZAtAngularDiameterDistance(cos, hubbleDistance(70)*0.5, 0)
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	runTest(func(float64) float64 { return zLow }, 0, 1, zTol, t, 0)
+	if !math.IsNaN(zHigh) {
+		t.Errorf("Expected no high-redshift branch, got %v", zHigh)
+	}
+}
+
+func TestZAtDistanceErrors(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	// The comoving distance to z=∞ is ~14.2 Gpc
+	if _, err := ZAtComovingDistance(cos, 20000, 0); !errors.Is(err, ErrNoRedshift) {
+		t.Errorf("Expected ErrNoRedshift beyond the horizon, got %v", err)
+	}
+	if _, err := ZAtLuminosityDistance(cos, -1, 0); !errors.Is(err, ErrNoRedshift) {
+		t.Errorf("Expected ErrNoRedshift for a negative distance, got %v", err)
+	}
+	if z, err := ZAtComovingDistance(cos, 0, 0); (z != 0) || (err != nil) {
+		t.Errorf("Expected z=0 for zero distance, got %v, %v", z, err)
+	}
+
+	bounce := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 1.8}
+	zMax := 0.7862996478468904
+	if _, err := ZAtComovingDistance(bounce, bounce.ComovingDistance(zMax)+100, 0); !errors.Is(err, ErrNoRedshift) {
+		t.Errorf("Expected ErrNoRedshift beyond the bounce, got %v", err)
+	}
+	z, err := ZAtComovingDistance(bounce, bounce.ComovingDistance(0.5), 0)
+	if err != nil {
+		t.Errorf("Unexpected error below the bounce %v", err)
+	}
+	runTest(func(float64) float64 { return z }, 0, 0.5, zTol, t, 0)
+}
+
+var timeCosmologies = map[string]FLRW{
+	"FlatLCDM":          FlatLCDM{H0: 70, Om0: 0.3},
+	"FlatLCDMEdS":       FlatLCDM{H0: 70, Om0: 1},
+	"FlatLCDMRadiation": FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04},
+	"LambdaCDMOL":       LambdaCDM{H0: 70, Om0: 0, Ol0: 0.5},
+	"LambdaCDMOM":       LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0},
+	"LambdaCDMClosed":   LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9},
+	"FlatWCDMLambda":    FlatWCDM{H0: 70, Om0: 0.3, W0: -1},
+	"WACDM":             WACDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, WA: 0.2},
+}
+
+func TestZAtTime(t *testing.T) {
+	for name, cos := range timeCosmologies {
+		for _, z := range append([]float64{-0.5}, zInverse...) {
+			got, err := ZAtAge(cos, cos.Age(z), 0)
+			if err != nil {
+				t.Errorf("%s ZAtAge: unexpected error %v", name, err)
+			} else if math.Abs(got-z) > zTol*(1+z) {
+				t.Errorf("%s ZAtAge: expected z=%v, got %v", name, z, got)
+			}
+
+			lookbackTime := cos.Age(0) - cos.Age(z)
+			if z >= 0 {
+				lookbackTime = cos.LookbackTime(z)
+			}
+			got, err = ZAtLookbackTime(cos, lookbackTime, 0)
+			if err != nil {
+				t.Errorf("%s ZAtLookbackTime: unexpected error %v", name, err)
+			} else if math.Abs(got-z) > zTol*(1+z) {
+				t.Errorf("%s ZAtLookbackTime: expected z=%v, got %v", name, z, got)
+			}
+		}
+	}
+}
+
+// TestZAtAgeAnalytic checks the closed-form inverses against root-finding.
+func TestZAtAgeAnalytic(t *testing.T) {
+	for _, cos := range []FLRW{
+		FlatLCDM{H0: 70, Om0: 0.3},
+		FlatLCDM{H0: 70, Om0: 1},
+		LambdaCDM{H0: 70, Om0: 0, Ol0: 0.5},
+	} {
+		for _, age := range []float64{0.1, 1, 5, 10, 20} {
+			exp, ok := cos.(ageInverter).zAtAge(age)
+			if !ok {
+				t.Errorf("Expected a closed-form inverse for %v", cos)
+				continue
+			}
+			z, err := zAtDecreasing(cos.Age, age, 0, "age")
+			if err != nil {
+				t.Errorf("Unexpected error %v", err)
+			}
+			runTest(func(float64) float64 { return z }, age, exp, zTol*(1+exp), t, 0)
+		}
+	}
+	if _, ok := (FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725}).zAtAge(1); ok {
+		t.Errorf("Expected no closed-form inverse with radiation")
+	}
+}
+
+func TestZAtTimeErrors(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	if _, err := ZAtAge(cos, -1, 0); !errors.Is(err, ErrNoRedshift) {
+		t.Errorf("Expected ErrNoRedshift for a negative age, got %v", err)
+	}
+	if _, err := ZAtLookbackTime(cos, 20, 0); !errors.Is(err, ErrNoRedshift) {
+		t.Errorf("Expected ErrNoRedshift for a look-back time beyond the age, got %v", err)
+	}
+	wacdm := WACDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, WA: 0.2}
+	if _, err := ZAtLookbackTime(wacdm, 20, 0); !errors.Is(err, ErrNoRedshift) {
+		t.Errorf("Expected ErrNoRedshift for a look-back time beyond the age, got %v", err)
+	}
+	bounce := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 1.8}
+	if _, err := ZAtAge(bounce, 5, 0); !errors.Is(err, ErrNoRedshift) {
+		t.Errorf("Expected ErrNoRedshift without a big bang, got %v", err)
+	}
+}
@@ -108,3 +108,35 @@ func TestLambdaCDMEOm(t *testing.T) {
 	}
 	runTests(cos.ComovingDistance, zLambdaCDM, expVec, distTol, t)
 }
+
+// The elliptic integrals agree with the numerical integration
+// for open and closed cosmologies, including Om0 > 1 and Ol0 < 0.
+func TestLambdaCDMComovingDistanceElliptic(t *testing.T) {
+	in := Integration{Method: IntegrateGaussKronrod, RelTol: 1e-13}
+	for _, cos := range []LambdaCDM{
+		{H0: 70, Om0: 0.3, Ol0: 0.6},
+		{H0: 70, Om0: 0.3, Ol0: 0.9},
+		{H0: 70, Om0: 0.3, Ol0: 1.5},
+		{H0: 70, Om0: 1.5, Ol0: 0.2},
+		{H0: 70, Om0: 0.3, Ol0: -0.2},
+		{H0: 70, Om0: 0.05, Ol0: 0.2},
+	} {
+		ref := cos
+		ref.Integration = in
+		for _, z := range []float64{0.5, 1, 3, 10, 1100} {
+			obs, ok := cos.comovingDistanceZ1Z2Elliptic(0, z)
+			exp := ref.comovingDistanceZ1Z2Integrate(0, z)
+			if !ok || !(math.Abs(obs/exp-1) <= 1e-10) {
+				t.Errorf("%v: comoving distance to z=%v: expected %v, got %v (ok=%v)", cos, z, exp, obs, ok)
+			}
+			runTest(cos.ComovingDistance, z, obs, 0, t, 0)
+		}
+	}
+
+	// A closed universe with a bounce at z ~ 0.8 falls back to the integration.
+	bounce := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 1.8}
+	if _, ok := bounce.comovingDistanceZ1Z2Elliptic(0, 0.5); ok {
+		t.Errorf("%v: expected no elliptic comoving distance", bounce)
+	}
+	runTest(bounce.ComovingDistance, 0.5, bounce.comovingDistanceZ1Z2Integrate(0, 0.5), 0, t, 0)
+}
@@ -0,0 +1,70 @@
+package cosmo
+
+import (
+	"math"
+)
+
+// cmInAMpc is the number of centimeters in a megaparsec
+const cmInAMpc = kmInAMpc * 1e5 // cm/Mpc
+
+// c2Over4PiG is c^2 / (4 pi G), the prefactor of the critical surface density.  [Msun/Mpc]
+const c2Over4PiG = speedOfLightCmS * speedOfLightCmS /
+	(4 * math.Pi * gravitationalConstantCgs) * cmInAMpc / massOfSunG
+
+// CriticalSurfaceDensity is the critical surface density for lensing
+// of a source at zs by a lens at zl,
+//   Sigma_cr = c^2 / (4 pi G) D_s / (D_l D_ls)
+// as a physical surface density in the lens plane.  [Msun/Mpc^2]
+// It is +Inf if zs <= zl, where there is no lensing.
+func CriticalSurfaceDensity(cos FLRW, zl, zs float64) (densityMsunMpc2 float64) {
+	if zs <= zl {
+		return math.Inf(1)
+	}
+	dl := cos.AngularDiameterDistance(zl)
+	ds := cos.AngularDiameterDistance(zs)
+	dls := cos.AngularDiameterDistanceZ1Z2(zl, zs)
+	return c2Over4PiG * ds / (dl * dls)
+}
+
+// TimeDelayDistance is the time-delay distance of a lens at zl and a source at zs,
+//   D_dt = (1 + zl) D_l D_s / D_ls
+// which sets the time delays between multiple images.
+// It is +Inf if zs <= zl, where there is no lensing.
+//   Refsdal, 1964, MNRAS, 128, 307
+func TimeDelayDistance(cos FLRW, zl, zs float64) (distanceMpc float64) {
+	if zs <= zl {
+		return math.Inf(1)
+	}
+	dl := cos.AngularDiameterDistance(zl)
+	ds := cos.AngularDiameterDistance(zs)
+	dls := cos.AngularDiameterDistanceZ1Z2(zl, zs)
+	return (1 + zl) * dl * ds / dls
+}
+
+// EinsteinRadiusPointMass is the Einstein radius of a point mass massMsun at zl
+// lensing a source at zs,
+//   theta_E = sqrt(4 G M / c^2 D_ls / (D_l D_s))
+// It is 0 if zs <= zl.
+func EinsteinRadiusPointMass(cos FLRW, massMsun, zl, zs float64) (angleArcsec float64) {
+	if zs <= zl {
+		return 0
+	}
+	dl := cos.AngularDiameterDistance(zl)
+	ds := cos.AngularDiameterDistance(zs)
+	dls := cos.AngularDiameterDistanceZ1Z2(zl, zs)
+	return math.Sqrt(massMsun/(math.Pi*c2Over4PiG)*dls/(dl*ds)) * arcsecInARadian
+}
+
+// EinsteinRadiusSIS is the Einstein radius of a singular isothermal sphere
+// with velocity dispersion sigmaKmS at zl lensing a source at zs,
+//   theta_E = 4 pi (sigma / c)^2 D_ls / D_s
+// It is 0 if zs <= zl.
+func EinsteinRadiusSIS(cos FLRW, sigmaKmS, zl, zs float64) (angleArcsec float64) {
+	if zs <= zl {
+		return 0
+	}
+	ds := cos.AngularDiameterDistance(zs)
+	dls := cos.AngularDiameterDistanceZ1Z2(zl, zs)
+	s := sigmaKmS / SpeedOfLightKmS
+	return 4 * math.Pi * s * s * dls / ds * arcsecInARadian
+}
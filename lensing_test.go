@@ -0,0 +1,74 @@
+package cosmo
+
+import (
+	"math"
+	"testing"
+)
+
+// TestAngularDiameterDistanceZ1Z2 checks D_A(z1, z2) against
+//   D_M(z1, z2) = D_M2 sqrt(1 + Ok0 D_M1^2 / D_H^2) - D_M1 sqrt(1 + Ok0 D_M2^2 / D_H^2)
+//   Hogg arXiv:9905116 Eq. 19
+// which is valid for Ok0 >= 0, and for Ok0 < 0 while D_M1, D_M2 stay before the equator.
+func TestAngularDiameterDistanceZ1Z2(t *testing.T) {
+	z1, z2 := 0.5, 2.0
+	for _, cos := range []FLRW{
+		FlatLCDM{H0: 70, Om0: 0.3},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.5},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9},
+		WCDM{H0: 70, Om0: 0.3, Ol0: 0.9, W0: -0.9},
+		LambdaCDM{H0: 70, Om0: 0, Ol0: 0},
+	} {
+		dm1, dm2 := cos.ComovingTransverseDistance(z1), cos.ComovingTransverseDistance(z2)
+		dH := cos.HubbleDistance()
+		Ok0 := cos.Ok0()
+		exp := (dm2*math.Sqrt(1+Ok0*dm1*dm1/(dH*dH)) - dm1*math.Sqrt(1+Ok0*dm2*dm2/(dH*dH))) / (1 + z2)
+		runTest(func(z2 float64) float64 { return cos.AngularDiameterDistanceZ1Z2(z1, z2) }, z2, exp, distTol, t, 0)
+		runTest(func(z2 float64) float64 { return cos.AngularDiameterDistanceZ1Z2(0, z2) }, z2, cos.AngularDiameterDistance(z2), distTol, t, 0)
+	}
+}
+
+// c^2 / (4 pi G) = 1.6629e18 Msun/Mpc
+func TestCriticalSurfaceDensity(t *testing.T) {
+	runTest(func(float64) float64 { return c2Over4PiG }, 0, 1.66291e18, 1e-5*1.66291e18, t, 0)
+
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	zl, zs := 0.5, 2.0
+	dl, ds := cos.AngularDiameterDistance(zl), cos.AngularDiameterDistance(zs)
+	dls := cos.AngularDiameterDistanceZ1Z2(zl, zs)
+	exp := 1.66291e18 * ds / (dl * dls)
+	runTest(func(float64) float64 { return CriticalSurfaceDensity(cos, zl, zs) }, 0, exp, 1e-5*exp, t, 0)
+	if s := CriticalSurfaceDensity(cos, zs, zl); !math.IsInf(s, 1) {
+		t.Errorf("Expected +Inf for a source in front of the lens, got %v", s)
+	}
+
+	exp = (1 + zl) * dl * ds / dls
+	runTest(func(float64) float64 { return TimeDelayDistance(cos, zl, zs) }, 0, exp, distTol, t, 0)
+	runTest(func(float64) float64 { return TimeDelayDistance(cos, zs, zl) }, 0, math.Inf(1), 0, t, 0)
+	runTest(func(float64) float64 { return TimeDelayDistance(cos, zl, zl) }, 0, math.Inf(1), 0, t, 0)
+}
+
+// The mean convergence within the Einstein radius is one:
+// the projected mass within R_E = D_l theta_E is pi Sigma_cr R_E^2.
+// A singular isothermal sphere has projected mass pi sigma^2 R / G within R.
+func TestEinsteinRadius(t *testing.T) {
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.6}
+	zl, zs := 0.3, 1.5
+	sigmaCr := CriticalSurfaceDensity(cos, zl, zs)
+	dl := cos.AngularDiameterDistance(zl)
+
+	M := 1e12
+	rE := dl * EinsteinRadiusPointMass(cos, M, zl, zs) / arcsecInARadian
+	runTest(func(float64) float64 { return math.Pi * sigmaCr * rE * rE }, 0, M, 1e-9*M, t, 0)
+
+	// G = 4.30092e-9 Mpc (km/s)^2 / Msun
+	sigma := 250.0
+	rE = dl * EinsteinRadiusSIS(cos, sigma, zl, zs) / arcsecInARadian
+	exp := math.Pi * sigma * sigma * rE / 4.30092e-9
+	runTest(func(float64) float64 { return math.Pi * sigmaCr * rE * rE }, 0, exp, 1e-5*exp, t, 0)
+
+	// theta_E = 4 pi (sigma/c)^2 D_ls/D_s with 206264.806 arcsec/rad
+	exp = 4 * math.Pi * math.Pow(sigma/SpeedOfLightKmS, 2) * cos.AngularDiameterDistanceZ1Z2(zl, zs) / cos.AngularDiameterDistance(zs) * 206264.806
+	runTest(func(float64) float64 { return EinsteinRadiusSIS(cos, sigma, zl, zs) }, 0, exp, 1e-6, t, 0)
+
+	runTest(func(float64) float64 { return EinsteinRadiusSIS(cos, sigma, zs, zl) }, 0, 0, 0, t, 0)
+}
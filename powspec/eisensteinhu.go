@@ -0,0 +1,199 @@
+package powspec
+
+import (
+	"fmt"
+	"math"
+)
+
+// defaultTcmb0 is the CMB temperature used by the transfer functions
+// for cosmologies without radiation, Tcmb0 = 0.  [K]
+//   Fixsen, 2009, ApJ, 707, 916
+const defaultTcmb0 = 2.7255
+
+// ehParams are the parameters shared by the Eisenstein & Hu transfer functions.
+type ehParams struct {
+	h     float64 // H0 / (100 km/s/Mpc)
+	om0   float64 // Matter density at z=0
+	omhh  float64 // Om0 h^2
+	obhh  float64 // Ob0 h^2
+	fb    float64 // Ob0 / Om0
+	theta float64 // Tcmb0 / 2.7 K
+}
+
+func newEHParams(cos Cosmology) (ehParams, error) {
+	h := cos.H(0) / 100
+	Om0, Ob0 := cos.Om(0), cos.Ob(0)
+	switch {
+	case !(Om0 > 0):
+		return ehParams{}, fmt.Errorf("powspec: Eisenstein & Hu needs Om0 > 0, got %v", Om0)
+	case !(Ob0 >= 0) || (Ob0 > Om0):
+		return ehParams{}, fmt.Errorf("powspec: Eisenstein & Hu needs 0 <= Ob0 <= Om0, got Ob0 = %v", Ob0)
+	}
+	Tcmb0 := cos.Tcmb(0)
+	if Tcmb0 == 0 {
+		Tcmb0 = defaultTcmb0
+	}
+	return ehParams{
+		h:     h,
+		om0:   Om0,
+		omhh:  Om0 * h * h,
+		obhh:  Ob0 * h * h,
+		fb:    Ob0 / Om0,
+		theta: Tcmb0 / 2.7,
+	}, nil
+}
+
+// EisensteinHu is the transfer function of Eisenstein & Hu, 1998, ApJ, 496, 605,
+// for cold dark matter and baryons, including the baryon acoustic oscillations.
+// It is accurate to a few percent for 0.025 < Om0 h^2 < 0.25 and Ob0/Om0 < 0.5.
+// Massive neutrinos and dark energy are not included in the shape.
+type EisensteinHu struct {
+	ehParams
+	kEq      float64 // Scale of the particle horizon at matter-radiation equality.  [1/Mpc]
+	s        float64 // Sound horizon at the drag epoch.  [Mpc]
+	kSilk    float64 // Silk damping scale.  [1/Mpc]
+	alphaC   float64
+	betaC    float64
+	alphaB   float64
+	betaB    float64
+	betaNode float64
+}
+
+// NewEisensteinHu sets up the Eisenstein & Hu (1998) transfer function for cos.
+// It needs Ob0 > 0.
+func NewEisensteinHu(cos Cosmology) (EisensteinHu, error) {
+	p, err := newEHParams(cos)
+	if err != nil {
+		return EisensteinHu{}, err
+	}
+	if !(p.obhh > 0) {
+		return EisensteinHu{}, fmt.Errorf("powspec: Eisenstein & Hu with BAO needs Ob0 > 0")
+	}
+	omhh, obhh, fb := p.omhh, p.obhh, p.fb
+	theta2 := p.theta * p.theta
+
+	// Eqs. 2-4: equality
+	zEq := 2.50e4 * omhh / (theta2 * theta2)
+	kEq := 7.46e-2 * omhh / theta2
+
+	// Eq. 4: drag epoch
+	b1 := 0.313 * math.Pow(omhh, -0.419) * (1 + 0.607*math.Pow(omhh, 0.674))
+	b2 := 0.238 * math.Pow(omhh, 0.223)
+	zD := 1291 * math.Pow(omhh, 0.251) / (1 + 0.659*math.Pow(omhh, 0.828)) *
+		(1 + b1*math.Pow(obhh, b2))
+
+	// Eqs. 5-6: baryon-to-photon momentum density ratio and sound horizon,
+	// with R at the drag epoch evaluated at 1+z_d, as in the authors' tf_fit.c.
+	R := func(z float64) float64 { return 31.5 * obhh / (theta2 * theta2) * (1000 / z) }
+	rD, rEq := R(1+zD), R(zEq)
+	s := 2 / (3 * kEq) * math.Sqrt(6/rEq) *
+		math.Log((math.Sqrt(1+rD)+math.Sqrt(rD+rEq))/(1+math.Sqrt(rEq)))
+
+	// Eq. 7: Silk damping
+	kSilk := 1.6 * math.Pow(obhh, 0.52) * math.Pow(omhh, 0.73) * (1 + math.Pow(10.4*omhh, -0.95))
+
+	// Eqs. 11-12: CDM suppression and shift
+	a1 := math.Pow(46.9*omhh, 0.670) * (1 + math.Pow(32.1*omhh, -0.532))
+	a2 := math.Pow(12.0*omhh, 0.424) * (1 + math.Pow(45.0*omhh, -0.582))
+	alphaC := math.Pow(a1, -fb) * math.Pow(a2, -fb*fb*fb)
+	bb1 := 0.944 / (1 + math.Pow(458*omhh, -0.708))
+	bb2 := math.Pow(0.395*omhh, -0.0266)
+	betaC := 1 / (1 + bb1*(math.Pow(1-fb, bb2)-1))
+
+	// Eqs. 14-15, 23-24: baryon suppression, shift, and node,
+	// with y = z_eq/(1+z_d) as in tf_fit.c.
+	y := zEq / (1 + zD)
+	sqy := math.Sqrt(1 + y)
+	G := y * (-6*sqy + (2+3*y)*math.Log((sqy+1)/(sqy-1)))
+	alphaB := 2.07 * kEq * s * math.Pow(1+rD, -0.75) * G
+	betaB := 0.5 + fb + (3-2*fb)*math.Sqrt(math.Pow(17.2*omhh, 2)+1)
+	betaNode := 8.41 * math.Pow(omhh, 0.435)
+
+	return EisensteinHu{
+		ehParams: p,
+		kEq:      kEq,
+		s:        s,
+		kSilk:    kSilk,
+		alphaC:   alphaC,
+		betaC:    betaC,
+		alphaB:   alphaB,
+		betaB:    betaB,
+		betaNode: betaNode,
+	}, nil
+}
+
+// SoundHorizon is the sound horizon at the drag epoch, Eq. 6.  [Mpc]
+func (eh EisensteinHu) SoundHorizon() (distanceMpc float64) {
+	return eh.s
+}
+
+// t0 is the pressureless transfer function, Eqs. 19-20.
+func (eh EisensteinHu) t0(k, alphaC, betaC float64) float64 {
+	q := k / (13.41 * eh.kEq)
+	L := math.Log(math.E + 1.8*betaC*q)
+	C := 14.2/alphaC + 386/(1+69.9*math.Pow(q, 1.08))
+	return L / (L + C*q*q)
+}
+
+// Transfer is the transfer function at wavenumber k.  [1/Mpc]
+// T -> 1 as k -> 0.
+func (eh EisensteinHu) Transfer(kMpc float64) (transfer float64) {
+	k := kMpc
+	ks := k * eh.s
+
+	// Eqs. 17-18: CDM
+	f := 1 / (1 + math.Pow(ks/5.4, 4))
+	tc := f*eh.t0(k, 1, eh.betaC) + (1-f)*eh.t0(k, eh.alphaC, eh.betaC)
+
+	// Eqs. 21-22: baryons
+	sTilde := eh.s / math.Cbrt(1+math.Pow(eh.betaNode/ks, 3))
+	tb := eh.t0(k, 1, 1)/(1+math.Pow(ks/5.2, 2)) +
+		eh.alphaB/(1+math.Pow(eh.betaB/ks, 3))*math.Exp(-math.Pow(k/eh.kSilk, 1.4))
+	tb *= sphericalBesselJ0(k * sTilde)
+
+	// Eq. 16
+	return eh.fb*tb + (1-eh.fb)*tc
+}
+
+// EisensteinHuNoWiggle is the zero-baryon-oscillation transfer function
+// of Eisenstein & Hu, 1998, ApJ, 496, 605, Section 4.2.
+// It follows the overall suppression by baryons without the oscillations,
+// e.g., to isolate the BAO feature.
+type EisensteinHuNoWiggle struct {
+	ehParams
+	alphaGamma float64
+	s          float64 // Approximate sound horizon, Eq. 26.  [Mpc]
+}
+
+// NewEisensteinHuNoWiggle sets up the Eisenstein & Hu (1998) no-wiggle transfer function for cos.
+func NewEisensteinHuNoWiggle(cos Cosmology) (EisensteinHuNoWiggle, error) {
+	p, err := newEHParams(cos)
+	if err != nil {
+		return EisensteinHuNoWiggle{}, err
+	}
+	omhh, obhh, fb := p.omhh, p.obhh, p.fb
+	// Eqs. 26, 31
+	s := 44.5 * math.Log(9.83/omhh) / math.Sqrt(1+10*math.Pow(obhh, 0.75))
+	alphaGamma := 1 - 0.328*math.Log(431*omhh)*fb + 0.38*math.Log(22.3*omhh)*fb*fb
+	return EisensteinHuNoWiggle{ehParams: p, alphaGamma: alphaGamma, s: s}, nil
+}
+
+// Transfer is the transfer function at wavenumber k.  [1/Mpc]
+// T -> 1 as k -> 0.
+func (eh EisensteinHuNoWiggle) Transfer(kMpc float64) (transfer float64) {
+	k := kMpc
+	// Eqs. 28-31
+	gammaEff := eh.om0 * eh.h * (eh.alphaGamma + (1-eh.alphaGamma)/(1+math.Pow(0.43*k*eh.s, 4)))
+	q := k * eh.theta * eh.theta / (gammaEff * eh.h)
+	L0 := math.Log(2*math.E + 1.8*q)
+	C0 := 14.2 + 731/(1+62.5*q)
+	return L0 / (L0 + C0*q*q)
+}
+
+// sphericalBesselJ0 is sin(x)/x
+func sphericalBesselJ0(x float64) float64 {
+	if math.Abs(x) < 1e-4 {
+		return 1 - x*x/6
+	}
+	return math.Sin(x) / x
+}
@@ -0,0 +1,88 @@
+package powspec
+
+import (
+	"fmt"
+	"github.com/wmwv/cosmo"
+	"math"
+	"testing"
+)
+
+// planck is close to the Planck 2015 TT,TE,EE+lowP+lensing+ext cosmology.
+var planck = cosmo.FlatLCDM{H0: 67.74, Om0: 0.3089, Ob0: 0.0486, Tcmb0: 2.7255, Neff: 3.046}
+
+func checkClose(name string, obs, exp, tol float64, t *testing.T) {
+	t.Helper()
+	if !(math.Abs(obs-exp) <= tol) {
+		t.Errorf("%s: expected %v +/- %v, got %v", name, exp, tol, obs)
+	}
+}
+
+func TestEisensteinHuLargeScale(t *testing.T) {
+	eh, err := NewEisensteinHu(planck)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	nw, err := NewEisensteinHuNoWiggle(planck)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	checkClose("EisensteinHu T(1e-6)", eh.Transfer(1e-6), 1, 1e-4, t)
+	checkClose("EisensteinHuNoWiggle T(1e-6)", nw.Transfer(1e-6), 1, 1e-4, t)
+}
+
+// TestEisensteinHuReference checks T(k) against the formulas of the authors'
+// tf_fit.c (TFset_parameters, TFfit_onek, and TFnowiggles) for the planck cosmology,
+// which use e = 2.718282.
+func TestEisensteinHuReference(t *testing.T) {
+	eh, _ := NewEisensteinHu(planck)
+	nw, _ := NewEisensteinHuNoWiggle(planck)
+	for _, tc := range []struct{ k, t, tNoWiggle float64 }{
+		{0.01, 0.6787276835062617, 0.6847268218564406},
+		{0.03, 0.3027949124734583, 0.3113181204009528},
+		{0.1, 0.08272921514860355, 0.08209966812352232},
+		{0.3, 0.016974229007154003, 0.01698550713354183},
+		{1.0, 0.0024645378303930965, 0.002442417545011241},
+	} {
+		checkClose(fmt.Sprintf("EisensteinHu T(%v)", tc.k), eh.Transfer(tc.k), tc.t, 1e-6*tc.t, t)
+		checkClose(fmt.Sprintf("EisensteinHuNoWiggle T(%v)", tc.k), nw.Transfer(tc.k), tc.tNoWiggle, 1e-6*tc.tNoWiggle, t)
+	}
+}
+
+// The approximate sound horizon of the no-wiggle form, EH98 Eq. 26,
+// is accurate to 2% for Ob0 h^2 > 0.0125.
+func TestEisensteinHuSoundHorizon(t *testing.T) {
+	eh, _ := NewEisensteinHu(planck)
+	nw, _ := NewEisensteinHuNoWiggle(planck)
+	checkClose("SoundHorizon", eh.SoundHorizon(), nw.s, 0.02*nw.s, t)
+}
+
+// TestEisensteinHuWiggles checks that the BAO oscillate around the no-wiggle form
+// and vanish on large and small scales.
+func TestEisensteinHuWiggles(t *testing.T) {
+	eh, _ := NewEisensteinHu(planck)
+	nw, _ := NewEisensteinHuNoWiggle(planck)
+	var sum float64
+	var n int
+	for k := 0.02; k < 0.5; k += 0.001 {
+		r := eh.Transfer(k) / nw.Transfer(k)
+		checkClose("T/T_nw", r, 1, 0.1, t)
+		sum += r
+		n++
+	}
+	checkClose("<T/T_nw>", sum/float64(n), 1, 0.01, t)
+	for _, k := range []float64{1e-4, 5} {
+		checkClose("T/T_nw", eh.Transfer(k)/nw.Transfer(k), 1, 0.02, t)
+	}
+}
+
+func TestEisensteinHuErrors(t *testing.T) {
+	if _, err := NewEisensteinHu(cosmo.FlatLCDM{H0: 70, Om0: 0.3}); err == nil {
+		t.Errorf("Expected an error for Ob0 = 0")
+	}
+	if _, err := NewEisensteinHuNoWiggle(cosmo.FlatLCDM{H0: 70, Om0: 0.3}); err != nil {
+		t.Errorf("Unexpected error %v for Ob0 = 0", err)
+	}
+	if _, err := NewEisensteinHuNoWiggle(cosmo.FlatLCDM{H0: 70, Om0: 0.3, Ob0: 0.4}); err == nil {
+		t.Errorf("Expected an error for Ob0 > Om0")
+	}
+}
@@ -0,0 +1,116 @@
+// Package powspec provides the linear matter power spectrum for the cosmologies of package cosmo.
+//
+// Transfer functions T(k) describe the processing of the primordial fluctuations
+// through radiation-matter equality and recombination.
+// EisensteinHu includes the baryon acoustic oscillations,
+// EisensteinHuNoWiggle is the smooth version without them.
+//
+// The linear power spectrum
+//   P(k, z) = A k^ns T(k)^2 D(z)^2
+// is normalized either to sigma8, the rms linear density fluctuation in spheres of 8 Mpc/h,
+// or to the amplitude A_s of the primordial curvature power spectrum at a pivot scale.
+// D(z) is the linear growth factor of cosmo.GrowthFactor.
+//
+// Sigma, SigmaM, and DlnSigmaDlnM give the variance of the linear density field
+// smoothed with a top-hat or Gaussian window on a comoving scale R or mass M.
+//
+// Wavenumbers are in 1/Mpc and P(k) in Mpc^3, without factors of h.
+package powspec
+
+import (
+	"fmt"
+	"github.com/wmwv/cosmo"
+	"math"
+)
+
+// Cosmology is a cosmology with the matter, baryon, and photon content
+// that set the shape of the transfer function.
+type Cosmology interface {
+	cosmo.BaryonFLRW
+}
+
+// TransferFunction is the matter transfer function T(k), normalized to T -> 1 as k -> 0.
+type TransferFunction interface {
+	Transfer(kMpc float64) (transfer float64)
+}
+
+// DefaultPivot is the pivot scale of the primordial power spectrum used by Planck.  [1/Mpc]
+const DefaultPivot = 0.05
+
+// Linear is the linear matter power spectrum at a given redshift.
+type Linear struct {
+	cos      Cosmology
+	transfer TransferFunction
+	ns       float64 // Spectral index of the primordial power spectrum
+	amp      float64 // P(k) = amp k^ns T(k)^2 at z=0
+	z        float64 // Redshift
+	growth   float64 // D(z) / D(0)
+}
+
+// NewLinearSigma8 is the linear power spectrum at z=0 with spectral index ns
+// normalized to sigma8 at z=0.
+func NewLinearSigma8(cos Cosmology, transfer TransferFunction, ns, sigma8 float64) (Linear, error) {
+	if !(sigma8 > 0) {
+		return Linear{}, fmt.Errorf("powspec: sigma8 must be positive, got %v", sigma8)
+	}
+	p := Linear{cos: cos, transfer: transfer, ns: ns, amp: 1, growth: 1}
+	s := p.Sigma8()
+	p.amp = sigma8 * sigma8 / (s * s)
+	return p, nil
+}
+
+// NewLinearAs is the linear power spectrum at z=0 with spectral index ns
+// normalized to the primordial curvature power spectrum
+//   Delta^2_R(k) = As (k / kPivot)^(ns-1)
+// kPivot is in 1/Mpc, e.g., DefaultPivot.
+//
+// The matter power spectrum during matter domination follows from the Poisson equation
+//   Delta^2(k) = (4/25) (c k / H0)^4 / Om0^2 T(k)^2 D(z)^2 Delta^2_R(k)
+// with D(z) normalized to the Einstein-de Sitter solution.
+//   Eisenstein & Hu, 1999, ApJ, 511, 5.  Eq. A3
+func NewLinearAs(cos Cosmology, transfer TransferFunction, ns, As, kPivot float64) (Linear, error) {
+	switch {
+	case !(As > 0):
+		return Linear{}, fmt.Errorf("powspec: As must be positive, got %v", As)
+	case !(kPivot > 0):
+		return Linear{}, fmt.Errorf("powspec: kPivot must be positive, got %v", kPivot)
+	}
+	Om0 := cos.Om(0)
+	hubbleDistance := cos.HubbleDistance()
+	D := cosmo.GrowthFactorEdS(cos, 0)
+	amp := 8 * math.Pi * math.Pi / 25 * math.Pow(hubbleDistance, 4) / (Om0 * Om0) *
+		D * D * As * math.Pow(kPivot, 1-ns)
+	return Linear{cos: cos, transfer: transfer, ns: ns, amp: amp, growth: 1}, nil
+}
+
+// AtRedshift is the same power spectrum scaled to redshift z
+// by the square of the linear growth factor.
+func (p Linear) AtRedshift(z float64) Linear {
+	p.z = z
+	p.growth = cosmo.GrowthFactor(p.cos, z)
+	return p
+}
+
+// Cosmology is the cosmology of the power spectrum.
+func (p Linear) Cosmology() Cosmology {
+	return p.cos
+}
+
+// Redshift is the redshift of the power spectrum.
+func (p Linear) Redshift() (z float64) {
+	return p.z
+}
+
+// P is the linear matter power spectrum at wavenumber k.  [Mpc^3]
+//   kMpc : wavenumber [1/Mpc]
+func (p Linear) P(kMpc float64) (powerMpc3 float64) {
+	T := p.transfer.Transfer(kMpc)
+	return p.amp * math.Pow(kMpc, p.ns) * T * T * p.growth * p.growth
+}
+
+// Sigma8 is the rms linear density fluctuation in spheres of radius 8 Mpc/h
+// at the redshift of the power spectrum.
+func (p Linear) Sigma8() (sigma8 float64) {
+	h := p.cos.H(0) / 100
+	return p.Sigma(8/h, WindowTopHat)
+}
@@ -0,0 +1,53 @@
+package powspec
+
+import (
+	"github.com/wmwv/cosmo"
+	"math"
+	"testing"
+)
+
+func TestLinearSigma8(t *testing.T) {
+	eh, _ := NewEisensteinHu(planck)
+	p, err := NewLinearSigma8(planck, eh, 0.9667, 0.8159)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	checkClose("Sigma8", p.Sigma8(), 0.8159, 1e-10, t)
+
+	z := 1.0
+	pz := p.AtRedshift(z)
+	D := cosmo.GrowthFactor(planck, z)
+	checkClose("Redshift", pz.Redshift(), z, 0, t)
+	checkClose("Sigma8(z)", pz.Sigma8(), 0.8159*D, 1e-10, t)
+	for _, k := range []float64{1e-3, 0.1, 1} {
+		checkClose("P(k, z)", pz.P(k), p.P(k)*D*D, 1e-12*p.P(k), t)
+	}
+}
+
+// The Planck 2015 A_s = 2.142e-9 at k = 0.05/Mpc gives sigma8 = 0.8159 from the Boltzmann codes.
+// The Eisenstein & Hu transfer function, without massive neutrinos, is good to a few percent.
+func TestLinearAs(t *testing.T) {
+	eh, _ := NewEisensteinHu(planck)
+	p, err := NewLinearAs(planck, eh, 0.9667, 2.142e-9, DefaultPivot)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	checkClose("Sigma8", p.Sigma8(), 0.8159, 0.03*0.8159, t)
+
+	// On large scales, P(k) ~ k^ns
+	k1, k2 := 1e-5, 2e-5
+	checkClose("ns", math.Log(p.P(k2)/p.P(k1))/math.Log(k2/k1), 0.9667, 1e-3, t)
+}
+
+func TestLinearErrors(t *testing.T) {
+	eh, _ := NewEisensteinHu(planck)
+	if _, err := NewLinearSigma8(planck, eh, 0.9667, 0); err == nil {
+		t.Errorf("Expected an error for sigma8 = 0")
+	}
+	if _, err := NewLinearAs(planck, eh, 0.9667, -1, DefaultPivot); err == nil {
+		t.Errorf("Expected an error for As < 0")
+	}
+	if _, err := NewLinearAs(planck, eh, 0.9667, 2e-9, 0); err == nil {
+		t.Errorf("Expected an error for kPivot = 0")
+	}
+}
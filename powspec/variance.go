@@ -0,0 +1,124 @@
+package powspec
+
+import (
+	"gonum.org/v1/gonum/integrate/quad"
+	"math"
+)
+
+// Window selects the filter used to smooth the linear density field.
+type Window int
+
+const (
+	// WindowTopHat is a spherical top-hat in real space,
+	//   W(x) = 3 (sin x - x cos x) / x^3
+	// enclosing M = (4 pi / 3) rho_m R^3.
+	WindowTopHat Window = iota
+	// WindowGaussian is a Gaussian in real space,
+	//   W(x) = exp(-x^2 / 2)
+	// enclosing M = (2 pi)^(3/2) rho_m R^3.
+	WindowGaussian
+)
+
+// Sigma is the rms linear density fluctuation smoothed on comoving scale R
+// at the redshift of the power spectrum.
+//   sigma^2(R) = Integral dln k k^3 P(k) / (2 pi^2) W(kR)^2
+//   R : [Mpc]
+func (p Linear) Sigma(R float64, w Window) (sigma float64) {
+	return math.Sqrt(p.variance(R, w))
+}
+
+// SigmaM is the rms linear density fluctuation smoothed on the scale
+// that encloses mass M in the mean matter density.
+//   M : [Msun]
+func (p Linear) SigmaM(M float64, w Window) (sigma float64) {
+	return p.Sigma(p.LagrangianRadius(M, w), w)
+}
+
+// DlnSigmaDlnM is the logarithmic slope dln sigma / dln M at mass M.  [Msun]
+//   dln sigma / dln M = (1/3) dln sigma / dln R
+//                     = 1 / (3 sigma^2) Integral dln k k^3 P(k) / (2 pi^2) W(x) x dW/dx
+// with x = kR.
+func (p Linear) DlnSigmaDlnM(M float64, w Window) (dlnSigmaDlnM float64) {
+	R := p.LagrangianRadius(M, w)
+	return p.dVarianceDlnR(R, w) / (6 * p.variance(R, w))
+}
+
+// LagrangianRadius is the comoving radius of the window that encloses mass M
+// in the mean matter density today.
+//   M : [Msun]
+//   R : [Mpc]
+func (p Linear) LagrangianRadius(M float64, w Window) (R float64) {
+	return math.Cbrt(M / (windowVolume(w) * meanMatterDensity(p.cos)))
+}
+
+// LagrangianMass is the mass enclosed by the window of comoving radius R
+// in the mean matter density today.
+//   R : [Mpc]
+//   M : [Msun]
+func (p Linear) LagrangianMass(R float64, w Window) (M float64) {
+	return windowVolume(w) * R * R * R * meanMatterDensity(p.cos)
+}
+
+// meanMatterDensity is the comoving mean matter density Om0 rho_crit,0.  [Msun/Mpc^3]
+func meanMatterDensity(cos Cosmology) (densityMsunMpc3 float64) {
+	return cos.Om(0) * cos.CriticalDensityMsunMpc3(0)
+}
+
+// windowVolume is the volume of the window divided by R^3.
+func windowVolume(w Window) float64 {
+	if w == WindowGaussian {
+		return math.Pow(2*math.Pi, 1.5)
+	}
+	return 4 * math.Pi / 3
+}
+
+// variance is sigma^2(R).
+func (p Linear) variance(R float64, w Window) float64 {
+	return p.integrateWindow(R, func(x float64) float64 {
+		W := window(x, w)
+		return W * W
+	})
+}
+
+// dVarianceDlnR is dsigma^2(R) / dln R.
+func (p Linear) dVarianceDlnR(R float64, w Window) float64 {
+	return p.integrateWindow(R, func(x float64) float64 {
+		return 2 * window(x, w) * x * windowDeriv(x, w)
+	})
+}
+
+// integrateWindow is the integral of the dimensionless power spectrum
+// times a function of x = kR over ln k.
+//   Integral dln k k^3 P(k) / (2 pi^2) f(kR)
+func (p Linear) integrateWindow(R float64, f func(x float64) float64) float64 {
+	n := 2000 // Integration will be n-point Gaussian quadrature
+	integrand := func(lnk float64) float64 {
+		k := math.Exp(lnk)
+		return k * k * k * p.P(k) / (2 * math.Pi * math.Pi) * f(k*R)
+	}
+	// The top-hat window falls off as (kR)^-2 and P(k) as k^-3 at large k,
+	// so the integrand is negligible outside of 1e-4 < kR < 1e3.
+	return quad.Fixed(integrand, math.Log(1e-4/R), math.Log(1e3/R), n, nil, 0)
+}
+
+// window is the Fourier transform W(x) of the window.
+func window(x float64, w Window) float64 {
+	if w == WindowGaussian {
+		return math.Exp(-x * x / 2)
+	}
+	if x < 1e-3 {
+		return 1 - x*x/10
+	}
+	return 3 * (math.Sin(x) - x*math.Cos(x)) / (x * x * x)
+}
+
+// windowDeriv is dW/dx.
+func windowDeriv(x float64, w Window) float64 {
+	if w == WindowGaussian {
+		return -x * math.Exp(-x*x/2)
+	}
+	if x < 1e-3 {
+		return -x / 5
+	}
+	return 3 * ((x*x-3)*math.Sin(x) + 3*x*math.Cos(x)) / (x * x * x * x)
+}
@@ -0,0 +1,60 @@
+package powspec
+
+import (
+	"math"
+	"testing"
+)
+
+// noTransfer is T(k) = 1, for a power-law P(k) ~ k^ns.
+type noTransfer struct{}
+
+func (noTransfer) Transfer(float64) float64 { return 1 }
+
+// For P(k) ~ k^n, sigma(R) ~ R^-(n+3)/2, so dln sigma / dln M = -(n+3)/6.
+func TestSigmaPowerLaw(t *testing.T) {
+	n := -1.5
+	p, err := NewLinearSigma8(planck, noTransfer{}, n, 0.8)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	for _, w := range []Window{WindowTopHat, WindowGaussian} {
+		for _, M := range []float64{1e12, 1e14, 1e15} {
+			checkClose("DlnSigmaDlnM", p.DlnSigmaDlnM(M, w), -(n+3)/6, 1e-5, t)
+		}
+		R1, R2 := 5.0, 10.0
+		checkClose("sigma(R2)/sigma(R1)", p.Sigma(R2, w)/p.Sigma(R1, w), math.Pow(R2/R1, -(n+3)/2), 1e-5, t)
+	}
+	// For a Gaussian window sigma^2(R) = A Gamma((n+3)/2) / (4 pi^2 R^(n+3)) with P(k) = A k^n.
+	R := 10.0
+	exp := p.amp * math.Gamma((n+3)/2) / (4 * math.Pi * math.Pi * math.Pow(R, n+3))
+	checkClose("sigma^2 Gaussian", p.variance(R, WindowGaussian), exp, 1e-5*exp, t)
+}
+
+func TestSigmaM(t *testing.T) {
+	eh, _ := NewEisensteinHu(planck)
+	p, _ := NewLinearSigma8(planck, eh, 0.9667, 0.8159)
+	h := planck.H0 / 100
+	checkClose("Sigma(8 Mpc/h)", p.Sigma(8/h, WindowTopHat), 0.8159, 1e-10, t)
+	// The mean matter density is 2.775e11 h^2 Om0 Msun/Mpc^3.
+	rho := 2.77536627e11 * h * h * planck.Om0
+	checkClose("rho_m", meanMatterDensity(planck), rho, 1e-5*rho, t)
+
+	for _, w := range []Window{WindowTopHat, WindowGaussian} {
+		M := 1e14
+		R := p.LagrangianRadius(M, w)
+		checkClose("LagrangianMass", p.LagrangianMass(R, w), M, 1e-10*M, t)
+		checkClose("SigmaM", p.SigmaM(M, w), p.Sigma(R, w), 1e-12, t)
+
+		// Finite-difference check of the slope.
+		dlnM := 1e-3
+		exp := (math.Log(p.SigmaM(M*math.Exp(dlnM), w)) - math.Log(p.SigmaM(M*math.Exp(-dlnM), w))) / (2 * dlnM)
+		checkClose("DlnSigmaDlnM", p.DlnSigmaDlnM(M, w), exp, 1e-6, t)
+
+		// sigma decreases with mass and grows with time.
+		if !(p.SigmaM(1e15, w) < p.SigmaM(1e12, w)) {
+			t.Errorf("Expected sigma(M) to decrease with M")
+		}
+		pz := p.AtRedshift(1)
+		checkClose("SigmaM(z)", pz.SigmaM(M, w), p.SigmaM(M, w)*pz.growth, 1e-12, t)
+	}
+}
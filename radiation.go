@@ -0,0 +1,90 @@
+package cosmo
+
+import "math"
+
+// Physical constants used to turn a CMB temperature into a density parameter.
+// CODATA 2018 values, as used by astropy.constants.
+const (
+	gravitationalConstantCgs = 6.67430e-8     // cm^3 / g / s^2
+	stefanBoltzmannCgs       = 5.670374419e-5 // erg / cm^2 / s / K^4
+	speedOfLightCmS          = 2.99792458e10  // cm / s
+	boltzmannConstantEvK     = 8.617333262e-5 // eV / K
+)
+
+// neutrinoToPhotonTemp is the ratio of the neutrino and photon temperatures
+// after electron-positron annihilation:  (4/11)^(1/3)
+const neutrinoToPhotonTemp = 0.7137658555036082
+
+// nuToPhotonDensityPerSpecies is the energy density of one species of
+// massless neutrino relative to the photon energy density:
+//   7/8 * (4/11)^(4/3)
+const nuToPhotonDensityPerSpecies = 0.22710731766
+
+// criticalDensity0 is the critical density at z=0 for a given H0.
+//   H0 : Hubble Parameter at z=0.  [km/s/Mpc]
+//   density : [g/cm^3]
+func criticalDensity0(H0 float64) (densityGCm3 float64) {
+	H0s := H0 / kmInAMpc // 1/s
+	return 3 * H0s * H0s / (8 * math.Pi * gravitationalConstantCgs)
+}
+
+// ogamma0 is the photon density parameter at z=0
+// for a black body at temperature Tcmb0.
+//   H0 : Hubble Parameter at z=0.  [km/s/Mpc]
+//   Tcmb0 : Temperature of the CMB at z=0.  [K]
+//
+// Follows the astropy definition:  a_B T^4 / c^2 / rho_crit
+// where a_B = 4 sigma_SB / c is the radiation constant.
+func ogamma0(H0, Tcmb0 float64) (photonDensity float64) {
+	if Tcmb0 == 0 {
+		return 0
+	}
+	aBc2 := 4 * stefanBoltzmannCgs /
+		(speedOfLightCmS * speedOfLightCmS * speedOfLightCmS) // g/cm^3/K^4
+	return aBc2 * math.Pow(Tcmb0, 4) / criticalDensity0(H0)
+}
+
+// onu0 is the neutrino density parameter at z=0.
+//   H0 : Hubble Parameter at z=0.  [km/s/Mpc]
+//   Tcmb0 : Temperature of the CMB at z=0.  [K]
+//   Neff : Effective number of neutrino species.
+//   mNu : Masses of the individual neutrino species.  [eV]
+func onu0(H0, Tcmb0, Neff float64, mNu []float64) (neutrinoDensity float64) {
+	return ogamma0(H0, Tcmb0) * nuRelativeDensity(0, Tcmb0, Neff, mNu)
+}
+
+// nuRelativeDensity is the neutrino energy density relative to
+// the photon energy density at redshift z.
+//   z : redshift
+//   Tcmb0 : Temperature of the CMB at z=0.  [K]
+//   Neff : Effective number of neutrino species.
+//   mNu : Masses of the individual neutrino species.  [eV]
+//
+// The number of species is floor(Neff), and Neff is shared equally among them.
+// Species beyond those listed in mNu are massless.
+// Massless neutrinos scale like radiation.
+// Massive neutrinos transition from radiation-like to matter-like scaling
+// following the fitting formula of
+//   Komatsu et al., 2011, ApJS, 192, 18.  Eq. 26
+// which is accurate to better than 0.5%.
+// This is the same approximation as used by astropy.cosmology.
+func nuRelativeDensity(z, Tcmb0, Neff float64, mNu []float64) (relativeDensity float64) {
+	if len(mNu) == 0 || Tcmb0 == 0 {
+		return nuToPhotonDensityPerSpecies * Neff
+	}
+	nNu := math.Max(math.Floor(Neff), float64(len(mNu)))
+	neffPerNu := Neff / nNu
+
+	const p = 1.83
+	const invp = 1 / p
+	const k = 0.3173
+	// mass / (k_B T_nu) at z=0 is nuY0 * m, with m in eV.
+	nuY0 := 1 / (boltzmannConstantEvK * neutrinoToPhotonTemp * Tcmb0)
+
+	relMass := nNu - float64(len(mNu))
+	for _, m := range mNu {
+		y := nuY0 * m / (1 + z)
+		relMass += math.Pow(1+math.Pow(k*y, p), invp)
+	}
+	return nuToPhotonDensityPerSpecies * neffPerNu * relMass
+}
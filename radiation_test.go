@@ -0,0 +1,178 @@
+package cosmo
+
+import (
+	"math"
+	"testing"
+)
+
+const densityTol = 1e-12 // []
+
+// The photon density follows the astropy definition
+//   a_B / c^2 * Tcmb0^4 / rho_crit
+//   a_B / c^2 = 4 * 5.670374419e-5 / (2.99792458e10)^3 = 8.4180e-36 g/cm^3/K^4
+//   rho_crit(H0=70) = 9.20387e-30 g/cm^3
+func TestOgamma0(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04}
+	runTest(func(float64) float64 { return cos.Ogamma0() }, 0, 5.04318599e-5, densityTol, t, 0)
+	runTest(func(float64) float64 { return cos.Onu0() }, 0, 3.48184710e-5, densityTol, t, 0)
+
+	noRadiation := FlatLCDM{H0: 70, Om0: 0.3, Neff: 3.04}
+	runTest(func(float64) float64 { return noRadiation.Ogamma0() }, 0, 0, densityTol, t, 0)
+	runTest(func(float64) float64 { return noRadiation.Onu0() }, 0, 0, densityTol, t, 0)
+}
+
+// TestRadiationFlatE0 checks that the radiation density is taken out of
+// the dark energy (flat) or curvature (non-flat) budget
+// so that E(0) = 1 still holds.
+func TestRadiationFlatE0(t *testing.T) {
+	Tcmb0, Neff := 2.725, 3.04
+	for _, cos := range []FLRW{
+		FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: Tcmb0, Neff: Neff},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.7, Tcmb0: Tcmb0, Neff: Neff},
+		WCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, Tcmb0: Tcmb0, Neff: Neff},
+		WACDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, WA: 0.2, Tcmb0: Tcmb0, Neff: Neff},
+	} {
+		runTest(cos.E, 0, 1, eTol, t, 0)
+	}
+}
+
+// TestRadiationE checks E(z) against the explicit sum of the components
+//   E^2 = Or0 (1+z)^4 + Om0 (1+z)^3 + Ok0 (1+z)^2 + Ol0
+func TestRadiationE(t *testing.T) {
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.6, Tcmb0: 2.725, Neff: 3.04}
+	oR := 5.04318599e-5 + 3.48184710e-5
+	Ok0 := 1 - 0.3 - 0.6 - oR
+	z := 1000.0
+	opz := 1 + z
+	exp := oR*opz*opz*opz*opz + 0.3*opz*opz*opz + Ok0*opz*opz + 0.6
+	runTest(func(z float64) float64 { return cos.E(z) * cos.E(z) }, z, exp, 1e-6*exp, t, 0)
+	runTest(func(float64) float64 { return cos.Ok0() }, 0, Ok0, densityTol, t, 0)
+}
+
+// TestRadiationLambdaCDMFlat checks that a LambdaCDM with Ol0 = 1 - Om0 - Or0
+// falls back to FlatLCDM and agrees with it when radiation is included.
+func TestRadiationLambdaCDMFlat(t *testing.T) {
+	flat := FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04}
+	Ol0 := 1 - flat.Om0 - flat.Ogamma0() - flat.Onu0()
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: Ol0, Tcmb0: 2.725, Neff: 3.04}
+
+	zVec := []float64{0.5, 1.0, 2.0, 3.0}
+	for _, z := range zVec {
+		runTest(cos.ComovingDistance, z, flat.ComovingDistance(z), distTol, t, 0)
+		runTest(cos.LookbackTime, z, flat.LookbackTime(z), ageTol, t, 0)
+		runTest(cos.Age, z, flat.Age(z), ageTol, t, 0)
+	}
+}
+
+// TestRadiationReducesDistances checks that adding radiation at fixed Om0
+// shortens distances and ages, as radiation speeds up the early expansion.
+func TestRadiationReducesDistances(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	rad := FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04}
+	for _, z := range []float64{0.5, 1.0, 2.0, 3.0} {
+		if !(rad.ComovingDistance(z) < cos.ComovingDistance(z)) {
+			t.Errorf("Expected radiation to reduce comoving distance at z=%f: %f >= %f",
+				z, rad.ComovingDistance(z), cos.ComovingDistance(z))
+		}
+		if !(rad.Age(z) < cos.Age(z)) {
+			t.Errorf("Expected radiation to reduce age at z=%f: %f >= %f",
+				z, rad.Age(z), cos.Age(z))
+		}
+	}
+}
+
+var zMassiveNu = []float64{0.0, 1.0, 2.0, 10.0, 1000.0}
+
+// Neutrino density relative to the photon density for massive neutrinos.
+// From astropy/cosmology/tests/test_cosmology.py::test_massivenu_density
+// which compares against the exact formula (Eqs. 24/25 of Komatsu et al. 2011).
+// The fitting formula is only good to ~0.5%, which sets the tolerance.
+var testTableMassiveNu = map[string]struct {
+	cos    FlatLCDM
+	nurel  []float64 // nu_relative_density / (7/8 (4/11)^(4/3) Neff)
+	onu    []float64 // Onu(z)
+	relTol float64
+}{
+	// FlatLambdaCDM(75.0, 0.25, Tcmb0=3.0, Neff=3, m_nu=100 eV)
+	"MassiveNu100eV": {FlatLCDM{H0: 75, Om0: 0.25, Tcmb0: 3, Neff: 3, MNu: []float64{100, 100, 100}},
+		[]float64{171969, 85984.5, 57323, 15633.5, 171.801}, nil, 5e-3},
+	// FlatLambdaCDM(75.0, 0.25, Tcmb0=3.0, Neff=3, m_nu=0.25 eV)
+	"MassiveNu0.25eV": {FlatLCDM{H0: 75, Om0: 0.25, Tcmb0: 3, Neff: 3, MNu: []float64{0.25, 0.25, 0.25}},
+		[]float64{429.924, 214.964, 143.312, 39.1005, 1.11086},
+		[]float64{0.01890217, 0.05244681, 0.0638236, 0.06999286, 0.1344951}, 5e-3},
+	// FlatLambdaCDM(80.0, 0.30, Tcmb0=3.0, Neff=3, m_nu=0.01 eV)
+	"MassiveNu0.01eV": {FlatLCDM{H0: 80, Om0: 0.3, Tcmb0: 3, Neff: 3, MNu: []float64{0.01, 0.01, 0.01}},
+		[]float64{17.2347, 8.67345, 5.84348, 1.90671, 1.00021},
+		[]float64{0.00066599, 0.00172677, 0.0020732, 0.00268404, 0.0978313}, 5e-3},
+	// FlatLambdaCDM(80.0, 0.30, Tcmb0=3.0, Neff=3.04, m_nu=[0.0, 0.01, 0.25] eV)
+	"MassiveNuMixed": {FlatLCDM{H0: 80, Om0: 0.3, Tcmb0: 3, Neff: 3.04, MNu: []float64{0, 0.01, 0.25}},
+		nil,
+		[]float64{0.00584959, 0.01493142, 0.01772291, 0.01963451, 0.10227728}, 5e-3},
+}
+
+func TestTableMassiveNu(t *testing.T) {
+	for name, test := range testTableMassiveNu {
+		cos := test.cos
+		for i, z := range zMassiveNu {
+			if test.nurel != nil {
+				nurel := nuRelativeDensity(z, cos.Tcmb0, cos.Neff, cos.MNu) /
+					(nuToPhotonDensityPerSpecies * cos.Neff)
+				if math.Abs(nurel/test.nurel[i]-1) > test.relTol {
+					t.Errorf("Failed %s nu_relative_density at z=%f\n  Expected %f, return %f",
+						name, z, test.nurel[i], nurel)
+				}
+			}
+			if test.onu == nil {
+				continue
+			}
+			opz := 1 + z
+			onu := cos.Ogamma0() * nuRelativeDensity(z, cos.Tcmb0, cos.Neff, cos.MNu) *
+				opz * opz * opz * opz / (cos.E(z) * cos.E(z))
+			if math.Abs(onu/test.onu[i]-1) > test.relTol {
+				t.Errorf("Failed %s Onu at z=%f\n  Expected %f, return %f",
+					name, z, test.onu[i], onu)
+			}
+		}
+	}
+}
+
+// Calculated via Python AstroPy
+//   FlatLambdaCDM(75.0, 0.25, Tcmb0=3.0, Neff=3, m_nu=100 eV).efunc([0, 1])
+//   FlatLambdaCDM(80.0, 0.30, Tcmb0=3.0, Neff=3, m_nu=0.01 eV).efunc([1, 2])
+func TestMassiveNuE(t *testing.T) {
+	cos := FlatLCDM{H0: 75, Om0: 0.25, Tcmb0: 3, Neff: 3, MNu: []float64{100, 100, 100}}
+	runTest(cos.E, 0, 1, eTol, t, 0)
+	runTest(cos.E, 1, 7.46144727668, 5e-3*7.46144727668, t, 0)
+
+	cos = FlatLCDM{H0: 80, Om0: 0.3, Tcmb0: 3, Neff: 3, MNu: []float64{0.01, 0.01, 0.01}}
+	runTests(cos.E, []float64{1, 2}, []float64{1.76225893, 2.97022048}, 1e-4*3, t)
+	runTests(cos.Einv, []float64{1, 2}, []float64{0.5674535, 0.33667534}, 1e-4, t)
+}
+
+// TestMassiveNuFallbacks checks that the massive neutrinos are carried along
+// when the more general types fall back to simpler cosmologies.
+func TestMassiveNuFallbacks(t *testing.T) {
+	mNu := []float64{0.06, 0, 0}
+	flat := FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04, MNu: mNu}
+	Ol0 := 1 - flat.Om0 - flat.Ogamma0() - flat.Onu0()
+	lcdm := LambdaCDM{H0: 70, Om0: 0.3, Ol0: Ol0, Tcmb0: 2.725, Neff: 3.04, MNu: mNu}
+	wcdm := WCDM{H0: 70, Om0: 0.3, Ol0: Ol0, W0: -1, Tcmb0: 2.725, Neff: 3.04, MNu: mNu}
+	wacdm := WACDM{H0: 70, Om0: 0.3, Ol0: Ol0, W0: -1, Tcmb0: 2.725, Neff: 3.04, MNu: mNu}
+
+	for _, cos := range []FLRW{lcdm, wcdm, wacdm} {
+		for _, z := range []float64{0.5, 1.0, 2.0, 3.0} {
+			runTest(cos.ComovingDistance, z, flat.ComovingDistance(z), distTol, t, 0)
+			runTest(cos.LuminosityDistance, z, flat.LuminosityDistance(z), distTol, t, 0)
+			runTest(cos.LookbackTime, z, flat.LookbackTime(z), ageTol, t, 0)
+			runTest(cos.Age, z, flat.Age(z), ageTol, t, 0)
+		}
+	}
+}
+
+// TestMassiveNuMatterLike checks that very massive neutrinos today scale like matter:
+// a 100 eV neutrino has Onu0 h^2 ~ 3 * 100 / 93.14 and so dominates the expansion.
+func TestMassiveNuMatterLike(t *testing.T) {
+	cos := FlatLCDM{H0: 100, Om0: 0, Tcmb0: 2.7255, Neff: 3, MNu: []float64{100, 100, 100}}
+	exp := 3 * 100 / 93.14
+	runTest(func(float64) float64 { return cos.Onu0() }, 0, exp, 0.01*exp, t, 0)
+}
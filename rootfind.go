@@ -0,0 +1,122 @@
+package cosmo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var errNotBracketed = errors.New("cosmo: root not bracketed")
+
+const machineEpsilon = 2.220446049250313e-16
+
+// brent finds a root of f in [a, b] to within an absolute tolerance tol in x
+// using Brent's method: inverse quadratic interpolation and secant steps,
+// falling back to bisection whenever those do not converge fast enough.
+// f(a) and f(b) must have opposite signs.
+// The error wraps ErrNoRedshift if f is NaN inside [a, b]
+// or the search does not converge.
+//   Brent, 1973, Algorithms for Minimization without Derivatives, Ch. 4
+//   Press et al., Numerical Recipes, 3rd ed., Section 9.3
+func brent(f func(float64) float64, a, b, tol float64) (float64, error) {
+	fa, fb := f(a), f(b)
+	switch {
+	case fa == 0:
+		return a, nil
+	case fb == 0:
+		return b, nil
+	case math.IsNaN(fa) || math.IsNaN(fb) || (fa > 0) == (fb > 0):
+		return math.NaN(), errNotBracketed
+	}
+
+	c, fc := b, fb
+	var d, e float64
+	const maxIter = 200
+	for i := 0; i < maxIter; i++ {
+		if (fb > 0) == (fc > 0) {
+			// Keep the root between b and c.
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+		tol1 := 2*machineEpsilon*math.Abs(b) + 0.5*tol
+		xm := 0.5 * (c - b)
+		if math.Abs(xm) <= tol1 || fb == 0 {
+			return b, nil
+		}
+		if math.Abs(e) >= tol1 && math.Abs(fa) > math.Abs(fb) {
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				// Secant
+				p = 2 * xm * s
+				q = 1 - s
+			} else {
+				// Inverse quadratic interpolation
+				q = fa / fc
+				r := fb / fc
+				p = s * (2*xm*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (s - 1)
+			}
+			if p > 0 {
+				q = -q
+			}
+			p = math.Abs(p)
+			if 2*p < math.Min(3*xm*q-math.Abs(tol1*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				d = xm
+				e = d
+			}
+		} else {
+			d = xm
+			e = d
+		}
+		a, fa = b, fb
+		if math.Abs(d) > tol1 {
+			b += d
+		} else {
+			b += math.Copysign(tol1, xm)
+		}
+		fb = f(b)
+		if math.IsNaN(fb) {
+			return math.NaN(), fmt.Errorf("%w: root finding hit NaN at z=%v", ErrNoRedshift, b)
+		}
+	}
+	return math.NaN(), fmt.Errorf("%w: root finding did not converge in %d iterations, last z=%v",
+		ErrNoRedshift, maxIter, b)
+}
+
+// goldenMax finds the maximum of f in [a, b] to within tol in x
+// by golden-section search.
+// f must be unimodal in [a, b].
+// tol is raised to at least 4 machine epsilon relative to a and b,
+// below which the interval cannot shrink.
+func goldenMax(f func(float64) float64, a, b, tol float64) (x, fx float64) {
+	invPhi := (math.Sqrt(5) - 1) / 2
+	x1 := b - invPhi*(b-a)
+	x2 := a + invPhi*(b-a)
+	f1, f2 := f(x1), f(x2)
+	tol = math.Max(tol, 4*machineEpsilon*math.Max(math.Abs(a), math.Abs(b)))
+	const maxIter = 200
+	for i := 0; (i < maxIter) && (math.Abs(b-a) > tol); i++ {
+		if f1 > f2 {
+			b, x2, f2 = x2, x1, f1
+			x1 = b - invPhi*(b-a)
+			f1 = f(x1)
+		} else {
+			a, x1, f1 = x1, x2, f2
+			x2 = a + invPhi*(b-a)
+			f2 = f(x2)
+		}
+	}
+	if f1 > f2 {
+		return x1, f1
+	}
+	return x2, f2
+}
@@ -0,0 +1,309 @@
+package cosmo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Errors returned by NewTabulated besides a *ParameterError.
+var (
+	ErrToleranceUnreachable = errors.New("cosmo: Tabulated cannot reach the tolerance")
+	ErrNotTabulable         = errors.New("cosmo: Tabulated model is not finite over the range")
+)
+
+const (
+	tabulatedMinIntervals = 16
+	tabulatedMaxIntervals = 1 << 12
+)
+
+// Tabulated wraps an FLRW with cubic Hermite interpolation of its comoving distance,
+// look-back time, and age over a range of redshifts,
+// using the exact derivatives (1+z)/E(z) and 1/E(z),
+// which are much faster to evaluate than the integrals of the wrapped FLRW,
+// e.g., for Monte Carlo samples of many redshifts.
+// Outside the range, and for E(z), it calls the wrapped FLRW.
+//
+// Create a Tabulated with NewTabulated.
+// A Tabulated is not modified after it is created,
+// so it is safe for concurrent use as long as the wrapped FLRW is.
+type Tabulated struct {
+	wrapped    FLRW
+	zMin, zMax float64
+	xMin, xMax float64 // ln(1+z)
+	// The interpolators are functions of x = ln(1+z),
+	// of the comoving distance in units of D_H,
+	// and of the look-back time and ln age in units of t_H.
+	comoving cubicHermite
+	lookback cubicHermite
+	lnAge    cubicHermite
+}
+
+// NewTabulated tabulates cos for zMin <= z <= zMax.
+//   tol : Maximum relative error of ComovingDistance, LookbackTime, and Age.
+//
+// It doubles the number of points until the interpolation agrees with the methods of cos
+// to within tol halfway between all points, where the interpolation error is largest.
+// It returns a *ParameterError unless 0 <= zMin < zMax < Inf and 0 < tol < Inf.
+// It returns an error wrapping ErrToleranceUnreachable if the error stops decreasing before it reaches tol,
+// as the methods of cos are themselves only accurate to ~ 1e-10,
+// and one wrapping ErrNotTabulable if the methods of cos are not finite over the range.
+func NewTabulated(cos FLRW, zMin, zMax, tol float64) (Tabulated, error) {
+	if err := validateTabulated(zMin, zMax, tol); err != nil {
+		return Tabulated{}, err
+	}
+
+	prevErr := math.Inf(1)
+	for n := tabulatedMinIntervals; n <= tabulatedMaxIntervals; n *= 2 {
+		tab := newTabulated(cos, zMin, zMax, n)
+		maxErr := tab.maxRelativeError()
+		switch {
+		case math.IsNaN(maxErr):
+			return Tabulated{}, fmt.Errorf("%w: %v for z in [%v, %v]", ErrNotTabulable, cos, zMin, zMax)
+		case maxErr <= tol:
+			return tab, nil
+		case maxErr > prevErr/2:
+			return Tabulated{}, fmt.Errorf("%w: tol = %v, stopped at %v with %d intervals",
+				ErrToleranceUnreachable, tol, maxErr, n)
+		}
+		prevErr = maxErr
+	}
+	return Tabulated{}, fmt.Errorf("%w: tol = %v, stopped at %v with %d intervals",
+		ErrToleranceUnreachable, tol, prevErr, tabulatedMaxIntervals)
+}
+
+// validateTabulated checks the range and tolerance of NewTabulated.
+func validateTabulated(zMin, zMax, tol float64) error {
+	const cosmology = "Tabulated"
+	switch {
+	case !isFinite(zMin):
+		return &ParameterError{Cosmology: cosmology, Parameter: "zMin", Value: zMin, Err: ErrNotFinite}
+	case zMin < 0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "zMin", Value: zMin, Err: ErrNegative}
+	case !isFinite(zMax):
+		return &ParameterError{Cosmology: cosmology, Parameter: "zMax", Value: zMax, Err: ErrNotFinite}
+	case zMax <= zMin:
+		return &ParameterError{Cosmology: cosmology, Parameter: "zMax", Value: zMax, Err: ErrInconsistent,
+			Detail: fmt.Sprintf("must exceed zMin = %v", zMin)}
+	case !isFinite(tol):
+		return &ParameterError{Cosmology: cosmology, Parameter: "tol", Value: tol, Err: ErrNotFinite}
+	case tol <= 0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "tol", Value: tol, Err: ErrNotPositive}
+	}
+	return nil
+}
+
+// newTabulated tabulates cos on n equal intervals in ln(1+z).
+// The comoving distance and look-back time are integrated outwards from z=0
+// and the age inwards from zMax, so that none of them loses precision to cancellation.
+func newTabulated(cos FLRW, zMin, zMax float64, n int) Tabulated {
+	tab := Tabulated{
+		wrapped: cos,
+		zMin:    zMin,
+		zMax:    zMax,
+		xMin:    math.Log1p(zMin),
+		xMax:    math.Log1p(zMax),
+	}
+	x := make([]float64, n+1)
+	for i := range x {
+		x[i] = tab.xMin + (tab.xMax-tab.xMin)*float64(i)/float64(n)
+	}
+	x[n] = tab.xMax
+
+	dComovingDx := func(x float64) float64 {
+		opz := math.Exp(x)
+		return opz * cos.Einv(opz-1)
+	}
+	dTimeDx := func(x float64) float64 {
+		return cos.Einv(math.Expm1(x))
+	}
+	integrateComoving := newCellIntegrator(dComovingDx)
+	integrateTime := newCellIntegrator(dTimeDx)
+
+	comoving, dComoving := make([]float64, n+1), make([]float64, n+1)
+	lookback, dLookback := make([]float64, n+1), make([]float64, n+1)
+	lnAge, dLnAge := make([]float64, n+1), make([]float64, n+1)
+	var dc, tl float64
+	xPrev := 0.0
+	for i, xi := range x {
+		dc += integrateComoving(xPrev, xi)
+		tl += integrateTime(xPrev, xi)
+		xPrev = xi
+		comoving[i], dComoving[i] = dc, dComovingDx(xi)
+		lookback[i], dLookback[i] = tl, dTimeDx(xi)
+	}
+	age := cos.Age(zMax) / tab.hubbleTime()
+	for i := n; i >= 0; i-- {
+		if i < n {
+			age += integrateTime(x[i], x[i+1])
+		}
+		lnAge[i], dLnAge[i] = math.Log(age), -dLookback[i]/age
+	}
+
+	tab.comoving = newCubicHermite(x, comoving, dComoving)
+	tab.lookback = newCubicHermite(x, lookback, dLookback)
+	tab.lnAge = newCubicHermite(x, lnAge, dLnAge)
+	return tab
+}
+
+// maxRelativeError is the largest relative difference of ComovingDistance, LookbackTime, and Age
+// from those of the wrapped FLRW halfway between the tabulated points.
+// It is NaN if any of them is not finite.
+func (cos Tabulated) maxRelativeError() float64 {
+	x := cos.comoving.x
+	var maxErr float64
+	for i := 0; i < len(x)-1; i++ {
+		z := math.Expm1((x[i] + x[i+1]) / 2)
+		for _, f := range []struct {
+			tabulated, exact func(float64) float64
+		}{
+			{cos.ComovingDistance, cos.wrapped.ComovingDistance},
+			{cos.LookbackTime, cos.wrapped.LookbackTime},
+			{cos.Age, cos.wrapped.Age},
+		} {
+			exact := f.exact(z)
+			relErr := math.Abs(f.tabulated(z)/exact - 1)
+			if math.IsNaN(relErr) || math.IsInf(relErr, 0) {
+				return math.NaN()
+			}
+			maxErr = math.Max(maxErr, relErr)
+		}
+	}
+	return maxErr
+}
+
+func (cos Tabulated) String() string {
+	return fmt.Sprintf("Tabulated{%v, z: [%v, %v]}", cos.wrapped, cos.zMin, cos.zMax)
+}
+
+// Wrapped is the tabulated FLRW
+func (cos Tabulated) Wrapped() FLRW {
+	return cos.wrapped
+}
+
+// integration is the numerical integration of the wrapped FLRW
+func (cos Tabulated) integration() Integration {
+	return integrationOf(cos.wrapped)
+}
+
+// Range is the range of tabulated redshifts
+func (cos Tabulated) Range() (zMin, zMax float64) {
+	return cos.zMin, cos.zMax
+}
+
+// inRange reports whether z is tabulated, and if so also returns x = ln(1+z).
+func (cos Tabulated) inRange(z float64) (x float64, ok bool) {
+	if !(z >= cos.zMin) || !(z <= cos.zMax) {
+		return 0, false
+	}
+	x = math.Log1p(z)
+	return math.Min(math.Max(x, cos.xMin), cos.xMax), true
+}
+
+// Ok0 is the curvature density at z=0
+func (cos Tabulated) Ok0() (curvatureDensity float64) {
+	return cos.wrapped.Ok0()
+}
+
+// DistanceModulus is the magnitude difference between 1 Mpc and
+// the luminosity distance for the given z.
+func (cos Tabulated) DistanceModulus(z float64) (distanceModulusMag float64) {
+	return 5*math.Log10(cos.LuminosityDistance(z)) + 25
+}
+
+// LuminosityDistance is the radius of effective sphere over which the light has spread out
+func (cos Tabulated) LuminosityDistance(z float64) (distanceMpc float64) {
+	return (1 + z) * cos.ComovingTransverseDistance(z)
+}
+
+// AngularDiameterDistance is the ratio of physical transverse size to angular size
+func (cos Tabulated) AngularDiameterDistance(z float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistance(z) / (1 + z)
+}
+
+// AngularDiameterDistanceZ1Z2 is the angular diameter distance of an object at z2
+// as seen by an observer at z1, e.g., D_ls for a lens at z1 and a source at z2.
+func (cos Tabulated) AngularDiameterDistanceZ1Z2(z1, z2 float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistanceZ1Z2(z1, z2) / (1 + z2)
+}
+
+// ComovingTransverseDistance is the comoving distance at z as seen from z=0
+func (cos Tabulated) ComovingTransverseDistance(z float64) (distanceMpcRad float64) {
+	return comovingTransverseDistanceFromComoving(cos, cos.ComovingDistance(z))
+}
+
+// ComovingTransverseDistanceZ1Z2 is the comoving distance at z2 as seen from z1
+func (cos Tabulated) ComovingTransverseDistanceZ1Z2(z1, z2 float64) (distanceMpcRad float64) {
+	return comovingTransverseDistanceZ1Z2(cos, z1, z2)
+}
+
+// ComovingVolume is the comoving volume of the whole sky out to z.
+func (cos Tabulated) ComovingVolume(z float64) (volumeMpc3 float64) {
+	return comovingVolume(cos, z)
+}
+
+// ComovingVolumeZ1Z2 is the comoving volume of the whole sky between z1 and z2.
+func (cos Tabulated) ComovingVolumeZ1Z2(z1, z2 float64) (volumeMpc3 float64) {
+	return cos.ComovingVolume(z2) - cos.ComovingVolume(z1)
+}
+
+// DifferentialComovingVolume is the comoving volume per unit redshift
+// per steradian at z.
+func (cos Tabulated) DifferentialComovingVolume(z float64) (volumeMpc3Sr float64) {
+	return differentialComovingVolume(cos, z)
+}
+
+// HubbleDistance is the inverse of the Hubble parameter
+//   distance : [Mpc]
+func (cos Tabulated) HubbleDistance() float64 {
+	return cos.wrapped.HubbleDistance()
+}
+
+// ComovingDistance is the distance that is constant with the Hubble flow
+// expressed in the physical distance at z=0.
+func (cos Tabulated) ComovingDistance(z float64) (distanceMpc float64) {
+	x, ok := cos.inRange(z)
+	if !ok {
+		return cos.wrapped.ComovingDistance(z)
+	}
+	return cos.wrapped.HubbleDistance() * cos.comoving.at(x)
+}
+
+// ComovingDistanceZ1Z2 is the comoving distance between two z
+func (cos Tabulated) ComovingDistanceZ1Z2(z1, z2 float64) (distanceMpc float64) {
+	return cos.ComovingDistance(z2) - cos.ComovingDistance(z1)
+}
+
+// LookbackTime is the time from redshift 0 to z.
+func (cos Tabulated) LookbackTime(z float64) (timeGyr float64) {
+	x, ok := cos.inRange(z)
+	if !ok {
+		return cos.wrapped.LookbackTime(z)
+	}
+	return cos.hubbleTime() * cos.lookback.at(x)
+}
+
+// Age is the time from redshift ∞ to z.
+func (cos Tabulated) Age(z float64) (timeGyr float64) {
+	x, ok := cos.inRange(z)
+	if !ok {
+		return cos.wrapped.Age(z)
+	}
+	return cos.hubbleTime() * math.Exp(cos.lnAge.at(x))
+}
+
+func (cos Tabulated) hubbleTime() (timeGyr float64) {
+	return hubbleTime(SpeedOfLightKmS / cos.wrapped.HubbleDistance())
+}
+
+// E is the Hubble parameter as a fraction of its present value
+// of the wrapped FLRW.
+func (cos Tabulated) E(z float64) (fractionalHubbleParameter float64) {
+	return cos.wrapped.E(z)
+}
+
+// Einv is the inverse Hubble parameter
+// of the wrapped FLRW.
+func (cos Tabulated) Einv(z float64) (invFractionalHubbleParameter float64) {
+	return cos.wrapped.Einv(z)
+}
@@ -0,0 +1,109 @@
+package cosmo
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+)
+
+const tabulatedTol = 1e-8
+
+func TestTabulated(t *testing.T) {
+	for name, cos := range inverseCosmologies {
+		tab, err := NewTabulated(cos, 0, 10, tabulatedTol)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+			continue
+		}
+		for _, z := range []float64{0, 1e-6, 0.013, 0.37, 1, 2.9, 7.77, 10} {
+			for fname, f := range map[string]struct {
+				tabulated, exact func(float64) float64
+			}{
+				"ComovingDistance":           {tab.ComovingDistance, cos.ComovingDistance},
+				"ComovingTransverseDistance": {tab.ComovingTransverseDistance, cos.ComovingTransverseDistance},
+				"LuminosityDistance":         {tab.LuminosityDistance, cos.LuminosityDistance},
+				"ComovingVolume":             {tab.ComovingVolume, cos.ComovingVolume},
+				"LookbackTime":               {tab.LookbackTime, cos.LookbackTime},
+				"Age":                        {tab.Age, cos.Age},
+			} {
+				exp := f.exact(z)
+				// The volume goes as distance cubed.
+				if obs := f.tabulated(z); obs != exp && !(math.Abs(obs/exp-1) <= 3*tabulatedTol) {
+					t.Errorf("%s Tabulated %s(%v): expected %v, got %v", name, fname, z, exp, obs)
+				}
+			}
+		}
+		// Outside of the table
+		runTest(tab.ComovingDistance, 20, cos.ComovingDistance(20), 0, t, 0)
+		runTest(tab.Age, 20, cos.Age(20), 0, t, 0)
+	}
+}
+
+func TestTabulatedErrors(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3}
+	for _, tc := range []struct {
+		zMin, zMax, tol float64
+		parameter       string
+		err             error
+	}{
+		{-0.5, 1, 1e-8, "zMin", ErrNegative},
+		{math.NaN(), 1, 1e-8, "zMin", ErrNotFinite},
+		{1, 1, 1e-8, "zMax", ErrInconsistent},
+		{0, math.Inf(1), 1e-8, "zMax", ErrNotFinite},
+		{0, 1, 0, "tol", ErrNotPositive},
+		{0, 1, math.NaN(), "tol", ErrNotFinite},
+	} {
+		_, err := NewTabulated(cos, tc.zMin, tc.zMax, tc.tol)
+		var perr *ParameterError
+		if !errors.As(err, &perr) || (perr.Parameter != tc.parameter) || !errors.Is(err, tc.err) {
+			t.Errorf("NewTabulated(%v, %v, %v, %v): expected a *ParameterError for %s wrapping %v, got %v",
+				cos, tc.zMin, tc.zMax, tc.tol, tc.parameter, tc.err, err)
+		}
+	}
+	if _, err := NewTabulated(cos, 0, 1, 1e-20); !errors.Is(err, ErrToleranceUnreachable) {
+		t.Errorf("NewTabulated(%v, 0, 1, 1e-20): expected ErrToleranceUnreachable, got %v", cos, err)
+	}
+	// A bounce model with no big bang, where E(z) is NaN beyond the bounce at z ~ 0.5,
+	// so the methods are not finite over [0, 5].
+	bounce := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 2}
+	if _, err := NewTabulated(bounce, 0, 5, 1e-8); !errors.Is(err, ErrNotTabulable) {
+		t.Errorf("NewTabulated(%v, 0, 5, 1e-8): expected ErrNotTabulable, got %v", bounce, err)
+	}
+}
+
+func TestTabulatedConcurrent(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04}
+	tab, err := NewTabulated(cos, 0, 3, tabulatedTol)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	exp := tab.LuminosityDistance(1.5)
+	var wg sync.WaitGroup
+	results := make([]float64, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				results[i] = tab.LuminosityDistance(1.5)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, obs := range results {
+		runTest(func(float64) float64 { return obs }, 1.5, exp, 0, t, 0)
+	}
+}
+
+func BenchmarkTabulatedComovingDistance(b *testing.B) {
+	cos := FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04}
+	tab, err := NewTabulated(cos, 0, 3, tabulatedTol)
+	if err != nil {
+		b.Fatalf("Unexpected error %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tab.ComovingDistance(1.5)
+	}
+}
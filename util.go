@@ -76,6 +76,22 @@ func ageOM(z, Om0, H0 float64) (timeGyr float64) {
 			Om0*math.Pow(1-Om0, -3./2)*math.Asinh(math.Sqrt((1/Om0-1)/(1+z))))
 }
 
+// zAtAgeOL is the inverse of ageOL:
+// the redshift at which the age of a dark-energy + curvature only Universe is timeGyr.
+//   timeGyr : [Gyr]
+//   Ol0 : Omega_Lambda at z=0.  0 < Ol0 < 1
+//   H0 : Hubble Parameter at z=0.  [km/s/Mpc]
+func zAtAgeOL(timeGyr, Ol0, H0 float64) (z float64) {
+	x := math.Sqrt(Ol0) * timeGyr / hubbleTime(H0)
+	return 1/(math.Sqrt((1/Ol0)-1)*math.Sinh(x)) - 1
+}
+
+// zAtAgeEdS is the inverse of the age of an Einstein-de Sitter (Omega_M=1) Universe
+//   t = (2/3) t_H (1+z)^(-3/2)
+func zAtAgeEdS(timeGyr, H0 float64) (z float64) {
+	return math.Pow(3*timeGyr/(2*hubbleTime(H0)), -2./3) - 1
+}
+
 // comovingTransverseDistanceOM is the case of Omega_M+Omega_K=1
 //
 //   z : redshift
@@ -127,7 +143,12 @@ func comovingDistanceOMZ1Z2(z1, z2, Om0, H0 float64) (distanceMpc float64) {
 // comovingTransverseDistanceZ1Z2 handles the curvature logic and then calls
 // the underlying FLRW type ComovingDistanceZ1Z2 function.
 func comovingTransverseDistanceZ1Z2(cos FLRW, z1, z2 float64) (distanceMpcRad float64) {
-	comovingDistance := cos.ComovingDistanceZ1Z2(z1, z2)
+	return comovingTransverseDistanceFromComoving(cos, cos.ComovingDistanceZ1Z2(z1, z2))
+}
+
+// comovingTransverseDistanceFromComoving is the transverse comoving distance
+// that corresponds to a line-of-sight comoving distance.
+func comovingTransverseDistanceFromComoving(cos FLRW, comovingDistance float64) (distanceMpcRad float64) {
 	Ok0 := cos.Ok0()
 	// We don't need the hubbleDistance for OK0==0, but it's a trivial calculation.
 	hubbleDistance := cos.HubbleDistance()
@@ -159,3 +180,131 @@ func tElliptic(s float64) float64 {
 	z := m + 3 + 2*math.Sqrt(3)
 	return 4 * mathext.EllipticRF(x, y, z)
 }
+
+// tEllipticCurved is
+//   Integral_x^inf dt / sqrt(Om0 t^3 + Ok0 t^2 + Ol0)
+// with t = 1+z, the comoving distance from 1+z = x to infinity in units of the Hubble distance,
+// for matter, curvature, and a cosmological constant with Om0 > 0.
+// ok is false if the cubic has a real root >= x,
+// i.e., if the integral runs through a region where E(z) is not real.
+//
+// With all three roots e1 >= e2 >= e3 of the cubic real,
+//   Integral = 2 / sqrt(Om0) RF(x-e1, x-e2, x-e3)
+// With one real root e, a complex pair p +/- iq, and A^2 = (e-p)^2 + q^2,
+// the substitution x-e = A tan^2(phi/2) gives the Legendre form
+//   Integral = 1 / sqrt(Om0 A) F(phi, k),  cos(phi) = (x-e-A)/(x-e+A),  k^2 = (A+p-e)/(2A)
+//   F(phi, k) = sin(phi) RF(cos^2(phi), 1 - k^2 sin^2(phi), 1),  phi <= pi/2
+//   F(phi, k) = 2 RF(0, 1-k^2, 1) - F(pi-phi, k),  phi > pi/2
+//
+// See Kantowski, 2000, https://arxiv.org/abs/astro-ph/0002334
+// and Baes, Camps, Van De Putte, 2017, MNRAS, 468, 927 for these distances in Legendre form.
+func tEllipticCurved(x, Om0, Ok0, Ol0 float64) (integral float64, ok bool) {
+	real3, e1, e2, e3 := cubicRoots(Ok0/Om0, Ol0/Om0)
+	if real3 {
+		if !(x > e1) {
+			return math.NaN(), false
+		}
+		return 2 / math.Sqrt(Om0) * mathext.EllipticRF(x-e1, x-e2, x-e3), true
+	}
+	// e1 is the real root and e2 +/- i e3 the complex pair
+	e, p, q := e1, e2, e3
+	if !(x > e) {
+		return math.NaN(), false
+	}
+	u := x - e
+	A := math.Hypot(e-p, q)
+	k2 := (A + p - e) / (2 * A)
+	cosPhi := (u - A) / (u + A)
+	sinPhi := 2 * math.Sqrt(u*A) / (u + A)
+	F := sinPhi * mathext.EllipticRF(cosPhi*cosPhi, 1-k2*sinPhi*sinPhi, 1)
+	if cosPhi < 0 {
+		F = 2*mathext.EllipticRF(0, 1-k2, 1) - F
+	}
+	return F / math.Sqrt(Om0*A), true
+}
+
+// cubicRoots are the roots of t^3 + b t^2 + d = 0.
+// If real3, they are all real and e1 >= e2 >= e3.
+// Otherwise e1 is the real root and e2 +/- i e3 is the complex pair.
+//   Press et al., Numerical Recipes, 3rd ed., Section 5.6
+func cubicRoots(b, d float64) (real3 bool, e1, e2, e3 float64) {
+	// Depressed cubic y^3 + p y + q = 0 for t = y - b/3
+	p := -b * b / 3
+	q := 2*b*b*b/27 + d
+	disc := q*q/4 + p*p*p/27
+	if disc < 0 {
+		m := 2 * math.Sqrt(-p/3)
+		theta := math.Acos(math.Max(-1, math.Min(1, 3*q/(p*m)))) / 3
+		e1 = m*math.Cos(theta) - b/3
+		e2 = m*math.Cos(theta-2*math.Pi/3) - b/3
+		e3 = m*math.Cos(theta+2*math.Pi/3) - b/3
+		return true, e1, e2, e3
+	}
+	s := math.Sqrt(disc)
+	e := math.Cbrt(-q/2+s) + math.Cbrt(-q/2-s) - b/3
+	// The other two roots are those of t^2 + (b+e) t + (b+e) e
+	re := -(b + e) / 2
+	im2 := (b+e)*e - re*re
+	if im2 <= 0 {
+		// A double root
+		return true, math.Max(e, re), re, math.Min(e, re)
+	}
+	return false, e, re, math.Sqrt(im2)
+}
+
+// comovingVolume is the comoving volume of the whole sky out to z.
+// It handles the curvature logic in the same way as comovingTransverseDistanceZ1Z2.
+//
+// For comoving distance X, curvature radius R = D_H / sqrt(|Ok0|), and u = X/R,
+// the volume is
+//   V = 4 pi Integral_0^X S_k(x)^2 dx
+//     = 2 pi R^3 (sinh(2u) / 2 - u)  for Ok0 > 0
+//     = 2 pi R^3 (u - sin(2u) / 2)   for Ok0 < 0
+// This is equivalent to Hogg arXiv:9905116 Eq. 29,
+// but is not limited to X < pi/2 R in a closed universe.
+// Both forms cancel catastrophically for small u, i.e., nearly flat models,
+// where the series in s = Ok0 X^2 / D_H^2 is used instead,
+//   V = 4 pi / 3 X^3 (1 + s/5 + 2 s^2/105 + ...)
+func comovingVolume(cos FLRW, z float64) (volumeMpc3 float64) {
+	return comovingVolumeFromComoving(cos, cos.ComovingDistance(z))
+}
+
+// comovingVolumeFromComoving is the comoving volume of the whole sky
+// out to a line-of-sight comoving distance.
+func comovingVolumeFromComoving(cos FLRW, comovingDistance float64) (volumeMpc3 float64) {
+	Ok0 := cos.Ok0()
+	hubbleDistance := cos.HubbleDistance()
+	X := comovingDistance
+	s := Ok0 * (X / hubbleDistance) * (X / hubbleDistance)
+
+	switch {
+	case (Ok0 == 0) || (math.Abs(s) < 1):
+		// The terms of (sinh(2u)/2 - u) / (2/3 u^3) with u^2 = s
+		// decrease by at least a factor of 5 each.
+		sum, term := 1.0, 1.0
+		for m := 1; m < 20; m++ {
+			term *= 4 * s / float64((2*m+2)*(2*m+3))
+			sum += term
+			if math.Abs(term) <= machineEpsilon*sum {
+				break
+			}
+		}
+		return 4 * math.Pi / 3 * X * X * X * sum
+	case Ok0 > 0:
+		R := hubbleDistance / math.Sqrt(Ok0)
+		u := X / R
+		return 2 * math.Pi * R * R * R * (math.Sinh(2*u)/2 - u)
+	default:
+		R := hubbleDistance / math.Sqrt(-Ok0)
+		u := X / R
+		return 2 * math.Pi * R * R * R * (u - math.Sin(2*u)/2)
+	}
+}
+
+// differentialComovingVolume is the comoving volume per unit redshift
+// per unit solid angle at z.
+//   Hogg arXiv:9905116 Eq. 28
+func differentialComovingVolume(cos FLRW, z float64) (volumeMpc3Sr float64) {
+	comovingTransverseDistance := cos.ComovingTransverseDistance(z)
+	return cos.HubbleDistance() * comovingTransverseDistance * comovingTransverseDistance * cos.Einv(z)
+}
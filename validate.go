@@ -0,0 +1,158 @@
+package cosmo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Categories of invalid cosmological parameters.
+// A *ParameterError wraps one of these, so they can be checked with errors.Is.
+var (
+	ErrNotPositive  = errors.New("must be positive")
+	ErrNotFinite    = errors.New("must be finite")
+	ErrNegative     = errors.New("must not be negative")
+	ErrInconsistent = errors.New("inconsistent with the other parameters")
+)
+
+// ParameterError reports an unphysical or inconsistent cosmological parameter.
+type ParameterError struct {
+	Cosmology string  // Type of the cosmology, e.g., "LambdaCDM"
+	Parameter string  // Name of the offending parameter, e.g., "H0"
+	Value     float64 // Value of the offending parameter
+	Err       error   // One of ErrNotPositive, ErrNotFinite, ErrNegative, ErrInconsistent
+	Detail    string  // Optional further explanation
+}
+
+func (e *ParameterError) Error() string {
+	msg := fmt.Sprintf("cosmo: %s %s = %v %v", e.Cosmology, e.Parameter, e.Value, e.Err)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// Unwrap returns the category of the error.
+func (e *ParameterError) Unwrap() error {
+	return e.Err
+}
+
+func isFinite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+// validateH0 checks that H0 is positive and finite.
+func validateH0(cosmology string, H0 float64) error {
+	switch {
+	case !isFinite(H0):
+		return &ParameterError{Cosmology: cosmology, Parameter: "H0", Value: H0, Err: ErrNotFinite}
+	case H0 <= 0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "H0", Value: H0, Err: ErrNotPositive}
+	}
+	return nil
+}
+
+// validateFinite checks that each of the named parameters is finite.
+func validateFinite(cosmology string, names []string, values ...float64) error {
+	for i, v := range values {
+		if !isFinite(v) {
+			return &ParameterError{Cosmology: cosmology, Parameter: names[i], Value: v, Err: ErrNotFinite}
+		}
+	}
+	return nil
+}
+
+// validateMatter checks that the matter and baryon densities are finite and not negative,
+// and that the baryons are part of the matter.
+func validateMatter(cosmology string, Om0, Ob0 float64) error {
+	switch {
+	case !isFinite(Om0):
+		return &ParameterError{Cosmology: cosmology, Parameter: "Om0", Value: Om0, Err: ErrNotFinite}
+	case Om0 < 0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "Om0", Value: Om0, Err: ErrNegative}
+	case !isFinite(Ob0):
+		return &ParameterError{Cosmology: cosmology, Parameter: "Ob0", Value: Ob0, Err: ErrNotFinite}
+	case Ob0 < 0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "Ob0", Value: Ob0, Err: ErrNegative}
+	case Ob0 > Om0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "Ob0", Value: Ob0, Err: ErrInconsistent,
+			Detail: fmt.Sprintf("exceeds Om0 = %v", Om0)}
+	}
+	return nil
+}
+
+// Radiation is the optional radiation content given to the constructors,
+// NewFlatLCDM, NewLambdaCDM, etc.
+// The zero value is no radiation.
+type Radiation struct {
+	Tcmb0 float64   // Temperature of the CMB at z=0.  [K]
+	Neff  float64   // Effective number of neutrino species
+	MNu   []float64 // Masses of the neutrino species.  [eV]
+}
+
+// oneRadiation is the Radiation given to a constructor, if any.
+func oneRadiation(cosmology string, rad []Radiation) (Radiation, error) {
+	switch len(rad) {
+	case 0:
+		return Radiation{}, nil
+	case 1:
+		return rad[0], nil
+	}
+	return Radiation{}, &ParameterError{Cosmology: cosmology, Parameter: "Radiation", Value: float64(len(rad)),
+		Err: ErrInconsistent, Detail: "at most one Radiation can be given"}
+}
+
+// validateRadiation checks the parameters that set the photon and neutrino densities.
+func validateRadiation(cosmology string, Tcmb0, Neff float64, mNu []float64) error {
+	switch {
+	case !isFinite(Tcmb0):
+		return &ParameterError{Cosmology: cosmology, Parameter: "Tcmb0", Value: Tcmb0, Err: ErrNotFinite}
+	case Tcmb0 < 0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "Tcmb0", Value: Tcmb0, Err: ErrNegative}
+	case !isFinite(Neff):
+		return &ParameterError{Cosmology: cosmology, Parameter: "Neff", Value: Neff, Err: ErrNotFinite}
+	case Neff < 0:
+		return &ParameterError{Cosmology: cosmology, Parameter: "Neff", Value: Neff, Err: ErrNegative}
+	}
+	for i, m := range mNu {
+		name := fmt.Sprintf("MNu[%d]", i)
+		switch {
+		case !isFinite(m):
+			return &ParameterError{Cosmology: cosmology, Parameter: name, Value: m, Err: ErrNotFinite}
+		case m < 0:
+			return &ParameterError{Cosmology: cosmology, Parameter: name, Value: m, Err: ErrNegative}
+		}
+	}
+	if len(mNu) == 0 {
+		return nil
+	}
+	if float64(len(mNu)) > math.Floor(Neff) {
+		return &ParameterError{Cosmology: cosmology, Parameter: "Neff", Value: Neff, Err: ErrInconsistent,
+			Detail: fmt.Sprintf("%d neutrino masses given for floor(Neff) species", len(mNu))}
+	}
+	if Tcmb0 == 0 {
+		return &ParameterError{Cosmology: cosmology, Parameter: "Tcmb0", Value: Tcmb0, Err: ErrInconsistent,
+			Detail: "neutrino masses have no effect without a CMB temperature"}
+	}
+	return nil
+}
+
+// validateFlat checks that there is room left for dark energy
+// in a flat cosmology: Om0 + Ogamma0 + Onu0 <= 1.
+func validateFlat(cosmology string, Om0, Ogamma0, Onu0 float64) error {
+	if Om0+Ogamma0+Onu0 > 1 {
+		return &ParameterError{Cosmology: cosmology, Parameter: "Om0", Value: Om0, Err: ErrInconsistent,
+			Detail: fmt.Sprintf("Om0 + Ogamma0 + Onu0 = %v exceeds 1 with Ok0 = 0", Om0+Ogamma0+Onu0)}
+	}
+	return nil
+}
+
+// firstError returns the first non-nil error.
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
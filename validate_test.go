@@ -0,0 +1,134 @@
+package cosmo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNewValid(t *testing.T) {
+	if _, err := NewFlatLCDM(70, 0.3); err != nil {
+		t.Errorf("NewFlatLCDM: unexpected error %v", err)
+	}
+	if _, err := NewFlatLCDM(70, 1); err != nil {
+		t.Errorf("NewFlatLCDM EdS: unexpected error %v", err)
+	}
+	if _, err := NewLambdaCDM(70, 0.3, 0.7); err != nil {
+		t.Errorf("NewLambdaCDM: unexpected error %v", err)
+	}
+	if _, err := NewLambdaCDM(70, 0.3, -0.2); err != nil {
+		t.Errorf("NewLambdaCDM negative Ol0: unexpected error %v", err)
+	}
+	if _, err := NewWCDM(70, 0.3, 0.7, -0.9); err != nil {
+		t.Errorf("NewWCDM: unexpected error %v", err)
+	}
+	if _, err := NewWACDM(70, 0.3, 0.7, -0.9, 0.2); err != nil {
+		t.Errorf("NewWACDM: unexpected error %v", err)
+	}
+	if _, err := NewFlatWCDM(70, 0.3, -0.9); err != nil {
+		t.Errorf("NewFlatWCDM: unexpected error %v", err)
+	}
+	if _, err := NewFlatWACDM(70, 0.3, -0.9, 0.2); err != nil {
+		t.Errorf("NewFlatWACDM: unexpected error %v", err)
+	}
+
+	cos, _ := NewLambdaCDM(70, 0.3, 0.7)
+	exp := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.7}
+	if cos.String() != exp.String() {
+		t.Errorf("NewLambdaCDM: expected %v, got %v", exp, cos)
+	}
+}
+
+var testTableValidate = map[string]struct {
+	cos interface{ Validate() error }
+	par string
+	err error
+}{
+	"NegativeH0":         {LambdaCDM{H0: -70, Om0: 0.3, Ol0: 0.7}, "H0", ErrNotPositive},
+	"ZeroH0":             {FlatLCDM{H0: 0, Om0: 0.3}, "H0", ErrNotPositive},
+	"InfH0":              {FlatLCDM{H0: math.Inf(1), Om0: 0.3}, "H0", ErrNotFinite},
+	"NaNOm0":             {LambdaCDM{H0: 70, Om0: math.NaN(), Ol0: 0.7}, "Om0", ErrNotFinite},
+	"NegativeOm0":        {WCDM{H0: 70, Om0: -0.1, Ol0: 0.7, W0: -1}, "Om0", ErrNegative},
+	"NegativeOb0":        {FlatLCDM{H0: 70, Om0: 0.3, Ob0: -0.05}, "Ob0", ErrNegative},
+	"Ob0ExceedsOm0":      {LambdaCDM{H0: 70, Om0: 0.03, Ob0: 0.05, Ol0: 0.7}, "Ob0", ErrInconsistent},
+	"NaNOl0":             {WCDM{H0: 70, Om0: 0.3, Ol0: math.NaN(), W0: -1}, "Ol0", ErrNotFinite},
+	"NaNW0":              {WCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: math.NaN()}, "W0", ErrNotFinite},
+	"InfWA":              {WACDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -1, WA: math.Inf(-1)}, "WA", ErrNotFinite},
+	"NaNFlatW0":          {FlatWCDM{H0: 70, Om0: 0.3, W0: math.NaN()}, "W0", ErrNotFinite},
+	"NegativeTcmb0":      {FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: -2.725, Neff: 3.04}, "Tcmb0", ErrNegative},
+	"NegativeNeff":       {LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.7, Tcmb0: 2.725, Neff: -1}, "Neff", ErrNegative},
+	"NegativeMNu":        {FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04, MNu: []float64{0.06, -0.01}}, "MNu[1]", ErrNegative},
+	"TooManyMNu":         {FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 2.5, MNu: []float64{0.06, 0, 0}}, "Neff", ErrInconsistent},
+	"MNuWithoutTcmb0":    {FlatLCDM{H0: 70, Om0: 0.3, Neff: 3.04, MNu: []float64{0.06}}, "Tcmb0", ErrInconsistent},
+	"FlatOverfull":       {FlatLCDM{H0: 70, Om0: 1.2}, "Om0", ErrInconsistent},
+	"FlatRadiation":      {FlatWACDM{H0: 70, Om0: 1, W0: -1, Tcmb0: 2.725, Neff: 3.04}, "Om0", ErrInconsistent},
+	"WzDEScale":          {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, DEScale: func(z float64) float64 { return 2 }}, "DEScale(0)", ErrInconsistent},
+	"IntegrationMethod":  {WCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W0: -0.9, Integration: Integration{Method: 7}}, "Integration.Method", ErrInconsistent},
+	"IntegrationNaNTol":  {LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.7, Integration: Integration{RelTol: math.NaN()}}, "Integration.RelTol", ErrNotFinite},
+	"IntegrationNegTol":  {FlatWACDM{H0: 70, Om0: 0.3, W0: -1, Integration: Integration{RelTol: -1e-8}}, "Integration.RelTol", ErrNegative},
+	"IntegrationAbsTol":  {FlatLCDM{H0: 70, Om0: 0.3, Integration: Integration{AbsTol: math.Inf(1)}}, "Integration.AbsTol", ErrNotFinite},
+	"IntegrationMaxEval": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, Integration: Integration{MaxEval: -1}}, "Integration.MaxEval", ErrNegative},
+}
+
+func TestTableValidate(t *testing.T) {
+	for name, test := range testTableValidate {
+		err := test.cos.Validate()
+		var perr *ParameterError
+		switch {
+		case !errors.As(err, &perr):
+			t.Errorf("%s: expected a *ParameterError, got %v", name, err)
+		case perr.Parameter != test.par:
+			t.Errorf("%s: expected error in %s, got %v", name, test.par, err)
+		case !errors.Is(err, test.err):
+			t.Errorf("%s: expected %v, got %v", name, test.err, err)
+		}
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	cos, err := NewLambdaCDM(-70, math.NaN(), 0.7)
+	if !errors.Is(err, ErrNotPositive) {
+		t.Errorf("Expected H0 to be reported first, got %v", err)
+	}
+	if cos.H0 != 0 || cos.Om0 != 0 || cos.Ol0 != 0 {
+		t.Errorf("Expected zero value on error, got %v", cos)
+	}
+	exp := "cosmo: LambdaCDM H0 = -70 must be positive"
+	if err.Error() != exp {
+		t.Errorf("Expected error message %q, got %q", exp, err.Error())
+	}
+}
+
+// The constructors validate the radiation along with the other parameters.
+func TestNewRadiation(t *testing.T) {
+	rad := Radiation{Tcmb0: 2.725, Neff: 3.04, MNu: []float64{0.06}}
+	cos, err := NewFlatLCDM(70, 0.3, rad)
+	if err != nil {
+		t.Errorf("NewFlatLCDM: unexpected error %v", err)
+	}
+	exp := FlatLCDM{H0: 70, Om0: 0.3, Tcmb0: 2.725, Neff: 3.04, MNu: []float64{0.06}}
+	runTest(cos.E, 1100, exp.E(1100), 0, t, 0)
+
+	bad := Radiation{Tcmb0: -2.725, Neff: 3.04}
+	for name, f := range map[string]func() error{
+		"FlatLCDM":  func() error { _, err := NewFlatLCDM(70, 0.3, bad); return err },
+		"LambdaCDM": func() error { _, err := NewLambdaCDM(70, 0.3, 0.7, bad); return err },
+		"WCDM":      func() error { _, err := NewWCDM(70, 0.3, 0.7, -0.9, bad); return err },
+		"WACDM":     func() error { _, err := NewWACDM(70, 0.3, 0.7, -0.9, 0.2, bad); return err },
+		"FlatWCDM":  func() error { _, err := NewFlatWCDM(70, 0.3, -0.9, bad); return err },
+		"FlatWACDM": func() error { _, err := NewFlatWACDM(70, 0.3, -0.9, 0.2, bad); return err },
+		"WzCDM":     func() error { _, err := NewWzCDM(70, 0.3, 0.7, nil, bad); return err },
+	} {
+		if err := f(); !errors.Is(err, ErrNegative) {
+			t.Errorf("New%s: expected ErrNegative for Tcmb0 < 0, got %v", name, err)
+		}
+	}
+
+	// Massive neutrinos need a CMB temperature.
+	if _, err := NewLambdaCDM(70, 0.3, 0.7, Radiation{Neff: 3.04, MNu: []float64{0.06}}); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("Expected ErrInconsistent for MNu without Tcmb0, got %v", err)
+	}
+	if _, err := NewLambdaCDM(70, 0.3, 0.7, rad, rad); !errors.Is(err, ErrInconsistent) {
+		t.Errorf("Expected ErrInconsistent for two Radiations, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package cosmo
+
+import (
+	"math"
+)
+
+// squareDegreesInASteradian is (180/pi)^2
+const squareDegreesInASteradian = (180 / math.Pi) * (180 / math.Pi) // deg^2/sr
+
+// SurveyVolume is the comoving volume between z1 and z2
+// within a survey footprint of areaSqDeg square degrees.
+// The full sky is 4 pi (180/pi)^2 = 41252.96 square degrees.
+func SurveyVolume(cos FLRW, areaSqDeg, z1, z2 float64) (volumeMpc3 float64) {
+	fullSky := 4 * math.Pi * squareDegreesInASteradian
+	return cos.ComovingVolumeZ1Z2(z1, z2) * areaSqDeg / fullSky
+}
+
+// DifferentialSurveyVolume is the comoving volume per unit redshift at z
+// within a survey footprint of areaSqDeg square degrees.
+func DifferentialSurveyVolume(cos FLRW, areaSqDeg, z float64) (volumeMpc3 float64) {
+	return cos.DifferentialComovingVolume(z) * areaSqDeg / squareDegreesInASteradian
+}
@@ -0,0 +1,92 @@
+package cosmo
+
+import (
+	"gonum.org/v1/gonum/integrate/quad"
+	"math"
+	"testing"
+)
+
+const volumeRelTol = 1e-8
+
+// In an Einstein-de Sitter universe
+//   D_C = 2 D_H (1 - 1/sqrt(1+z))
+//   V = 4 pi / 3 D_C^3
+//   dV/dz/dOmega = D_H D_C^2 / (1+z)^(3/2)
+func TestComovingVolumeEdS(t *testing.T) {
+	cos := FlatLCDM{H0: 70, Om0: 1}
+	dH := hubbleDistance(70)
+	for _, z := range []float64{0.5, 1.0, 2.0, 3.0} {
+		dC := 2 * dH * (1 - 1/math.Sqrt(1+z))
+		exp := 4 * math.Pi / 3 * dC * dC * dC
+		runTest(cos.ComovingVolume, z, exp, volumeRelTol*exp, t, 0)
+		exp = dH * dC * dC / math.Pow(1+z, 3./2)
+		runTest(cos.DifferentialComovingVolume, z, exp, volumeRelTol*exp, t, 0)
+	}
+}
+
+// In an empty universe, Ok0 = 1,
+//   D_C = D_H ln(1+z)
+//   D_M = D_H sinh(ln(1+z)) = D_H z (2+z) / (2 (1+z))
+//   V = 2 pi D_H^3 (sinh(2 ln(1+z)) / 2 - ln(1+z))
+func TestComovingVolumeEmpty(t *testing.T) {
+	cos := LambdaCDM{H0: 70, Om0: 0, Ol0: 0}
+	dH := hubbleDistance(70)
+	for _, z := range []float64{0.5, 1.0, 2.0, 3.0} {
+		x := math.Log1p(z)
+		exp := 2 * math.Pi * dH * dH * dH * (math.Sinh(2*x)/2 - x)
+		runTest(cos.ComovingVolume, z, exp, volumeRelTol*exp, t, 0)
+		dM := dH * z * (2 + z) / (2 * (1 + z))
+		exp = dH * dM * dM / (1 + z)
+		runTest(cos.DifferentialComovingVolume, z, exp, volumeRelTol*exp, t, 0)
+	}
+}
+
+// TestComovingVolumeCurved checks that the volume is the integral
+// of the differential volume over the whole sky for open, flat, and closed models.
+// The closed model with Ol0=1.5 reaches past the equator of the 3-sphere,
+// D_C > pi/2 D_H/sqrt(-Ok0), by z=3,
+// which the arcsin form of Hogg Eq. 29 does not handle.
+func TestComovingVolumeCurved(t *testing.T) {
+	for _, cos := range []FLRW{
+		FlatLCDM{H0: 70, Om0: 0.3},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.5},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9},
+		WCDM{H0: 70, Om0: 0.3, Ol0: 0.9, W0: -0.9},
+		LambdaCDM{H0: 70, Om0: 2, Ol0: 0},
+		LambdaCDM{H0: 70, Om0: 0.3, Ol0: 1.5},
+	} {
+		for _, z := range []float64{0.5, 1.0, 3.0, 10.0} {
+			n := 200
+			exp := 4 * math.Pi * quad.Fixed(cos.DifferentialComovingVolume, 0, z, n, nil, 0)
+			runTest(cos.ComovingVolume, z, exp, 1e-6*exp, t, 0)
+		}
+	}
+}
+
+// Nearly flat models, e.g., with Ol0 = 1 - Om0 - Or0 rounded,
+// have the volume of the flat model rather than a catastrophic cancellation.
+func TestComovingVolumeNearlyFlat(t *testing.T) {
+	flat := FlatLCDM{H0: 70, Om0: 0.3}
+	for _, Ok0 := range []float64{1e-12, -1e-12, 1e-16, -1e-16} {
+		cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.7 - Ok0}
+		for _, z := range []float64{0.5, 1.0, 3.0} {
+			exp := flat.ComovingVolume(z)
+			runTest(cos.ComovingVolume, z, exp, volumeRelTol*exp, t, 0)
+			exp = flat.ComovingVolumeZ1Z2(z, 2*z)
+			runTest(func(z float64) float64 { return cos.ComovingVolumeZ1Z2(z, 2*z) }, z, exp, volumeRelTol*exp, t, 0)
+		}
+	}
+}
+
+func TestComovingVolumeZ1Z2(t *testing.T) {
+	cos := LambdaCDM{H0: 70, Om0: 0.3, Ol0: 0.9}
+	exp := cos.ComovingVolume(2) - cos.ComovingVolume(1)
+	runTest(func(float64) float64 { return cos.ComovingVolumeZ1Z2(1, 2) }, 0, exp, volumeRelTol*exp, t, 0)
+
+	fullSky := 4 * math.Pi * (180 / math.Pi) * (180 / math.Pi)
+	runTest(func(float64) float64 { return SurveyVolume(cos, fullSky, 1, 2) }, 0, exp, volumeRelTol*exp, t, 0)
+	runTest(func(float64) float64 { return SurveyVolume(cos, 100, 1, 2) }, 0, exp*100/41252.96125, volumeRelTol*exp, t, 0)
+
+	exp = cos.DifferentialComovingVolume(1) * 4 * math.Pi
+	runTest(func(float64) float64 { return DifferentialSurveyVolume(cos, fullSky, 1) }, 0, exp, volumeRelTol*exp, t, 0)
+}
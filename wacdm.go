@@ -2,7 +2,6 @@ package cosmo
 
 import (
 	"fmt"
-	"gonum.org/v1/gonum/integrate/quad"
 	"math"
 )
 
@@ -13,26 +12,131 @@ import (
 // w = w0 + wa * (1-a)
 // equation-of-state parameter for dark energy.
 type WACDM struct {
-	H0      float64 // Hubble constant at z=0.  [km/s/Mpc]
-	Om0     float64 // Matter Density at z=0
-	Ol0     float64 // Dark Energy density Lambda at z=0
-	W0      float64 // Dark energy equation-of-state parameter, w0 + wa*(1-a) = p/rho
-	WA      float64 // Dark energy equation-of-state parameter, w0 + wa*(1-a) = p/rho
-	Ogamma0 float64 // Photon density
-	Onu0    float64 // Neutrino density
+	H0          float64     // Hubble constant at z=0.  [km/s/Mpc]
+	Om0         float64     // Matter Density at z=0
+	Ob0         float64     // Baryon density at z=0, included in Om0
+	Ol0         float64     // Dark Energy density Lambda at z=0
+	W0          float64     // Dark energy equation-of-state parameter, w0 + wa*(1-a) = p/rho
+	WA          float64     // Dark energy equation-of-state parameter, w0 + wa*(1-a) = p/rho
+	Tcmb0       float64     // Temperature of the CMB at z=0.  [K]
+	Neff        float64     // Effective number of neutrino species
+	MNu         []float64   // Masses of the neutrino species.  [eV]
+	Integration Integration // Numerical integration of distances and times
 }
 
-// Tcmb0   float64 // Temperature of the CMB at z=0.  [K]
-// nuToPhotonDensity float64 // Neutrino density / photon density
-
 func (cos WACDM) String() string {
 	return fmt.Sprintf("WACDM{H0: %v, Om0: %v, Ol0: %v, W0: %v, WA: %v}",
 		cos.H0, cos.Om0, cos.Ol0, cos.W0, cos.WA)
 }
 
+// NewWACDM creates a WACDM and validates its parameters.
+// The radiation, if any, is given as an optional Radiation
+// and validated with the other parameters.
+func NewWACDM(H0, Om0, Ol0, W0, WA float64, rad ...Radiation) (WACDM, error) {
+	r, err := oneRadiation("WACDM", rad)
+	if err != nil {
+		return WACDM{}, err
+	}
+	cos := WACDM{H0: H0, Om0: Om0, Ol0: Ol0, W0: W0, WA: WA, Tcmb0: r.Tcmb0, Neff: r.Neff, MNu: r.MNu}
+	if err := cos.Validate(); err != nil {
+		return WACDM{}, err
+	}
+	return cos, nil
+}
+
 // Ok0 is the curvature density at z=0
 func (cos WACDM) Ok0() (curvatureDensity float64) {
-	return 1 - (cos.Om0 + cos.Ol0)
+	return 1 - (cos.Om0 + cos.Ol0 + cos.Ogamma0() + cos.Onu0())
+}
+
+// integration is the numerical integration of distances and times
+func (cos WACDM) integration() Integration {
+	return cos.Integration
+}
+
+// Ogamma0 is the photon density at z=0
+func (cos WACDM) Ogamma0() (photonDensity float64) {
+	return ogamma0(cos.H0, cos.Tcmb0)
+}
+
+// Onu0 is the neutrino density at z=0
+func (cos WACDM) Onu0() (neutrinoDensity float64) {
+	return onu0(cos.H0, cos.Tcmb0, cos.Neff, cos.MNu)
+}
+
+// Om is the matter density at z as a fraction of the critical density at z
+func (cos WACDM) Om(z float64) (matterDensity float64) {
+	return cos.Om0 * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Ob is the baryon density at z as a fraction of the critical density at z
+func (cos WACDM) Ob(z float64) (baryonDensity float64) {
+	return cos.Ob0 * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Tcmb is the temperature of the CMB at z.  [K]
+func (cos WACDM) Tcmb(z float64) (temperatureK float64) {
+	return cos.Tcmb0 * (1 + z)
+}
+
+// Ode is the dark energy density at z as a fraction of the critical density at z
+func (cos WACDM) Ode(z float64) (darkEnergyDensity float64) {
+	return cos.Ol0 * cos.deScale(z) / (cos.E(z) * cos.E(z))
+}
+
+// Ok is the curvature density at z as a fraction of the critical density at z
+func (cos WACDM) Ok(z float64) (curvatureDensity float64) {
+	return cos.Ok0() * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Ogamma is the photon density at z as a fraction of the critical density at z
+func (cos WACDM) Ogamma(z float64) (photonDensity float64) {
+	return cos.Ogamma0() * (1 + z) * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Onu is the neutrino density at z as a fraction of the critical density at z
+func (cos WACDM) Onu(z float64) (neutrinoDensity float64) {
+	return cos.Ogamma0() * nuRelativeDensity(z, cos.Tcmb0, cos.Neff, cos.MNu) *
+		(1 + z) * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// H is the Hubble parameter at z.  [km/s/Mpc]
+func (cos WACDM) H(z float64) (hubbleParameterKmSMpc float64) {
+	return cos.H0 * cos.E(z)
+}
+
+// CriticalDensity is the critical density at z.  [g/cm^3]
+func (cos WACDM) CriticalDensity(z float64) (densityGCm3 float64) {
+	return criticalDensity0(cos.H0) * cos.E(z) * cos.E(z)
+}
+
+// CriticalDensityMsunMpc3 is the critical density at z.  [Msun/Mpc^3]
+func (cos WACDM) CriticalDensityMsunMpc3(z float64) (densityMsunMpc3 float64) {
+	return cos.CriticalDensity(z) * gCm3ToMsunMpc3
+}
+
+// lambdaMatter returns Om0 and Ok0 if the cosmology has only
+// matter, curvature, and a cosmological constant, for which
+// the linear growth factor is given by the Heath integral.
+func (cos WACDM) lambdaMatter() (Om0, Ok0 float64, ok bool) {
+	if cos.WA != 0 {
+		return 0, 0, false
+	}
+	wcdm_cos := WCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Ol0: cos.Ol0, W0: cos.W0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
+	return wcdm_cos.lambdaMatter()
+}
+
+// Validate checks that the parameters describe a physical cosmology.
+// The error, if any, is a *ParameterError for the first problem found.
+func (cos WACDM) Validate() error {
+	const name = "WACDM"
+	return firstError(
+		validateH0(name, cos.H0),
+		validateMatter(name, cos.Om0, cos.Ob0),
+		validateFinite(name, []string{"Ol0", "W0", "WA"}, cos.Ol0, cos.W0, cos.WA),
+		validateRadiation(name, cos.Tcmb0, cos.Neff, cos.MNu),
+		cos.Integration.validate(name),
+	)
 }
 
 // DistanceModulus is the magnitude difference between 1 Mpc and
@@ -51,6 +155,12 @@ func (cos WACDM) AngularDiameterDistance(z float64) (distanceMpcRad float64) {
 	return cos.ComovingTransverseDistance(z) / (1 + z)
 }
 
+// AngularDiameterDistanceZ1Z2 is the angular diameter distance of an object at z2
+// as seen by an observer at z1, e.g., D_ls for a lens at z1 and a source at z2.
+func (cos WACDM) AngularDiameterDistanceZ1Z2(z1, z2 float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistanceZ1Z2(z1, z2) / (1 + z2)
+}
+
 // ComovingTransverseDistance is the comoving distance at z as seen from z=0
 func (cos WACDM) ComovingTransverseDistance(z float64) (distanceMpcRad float64) {
 	return cos.ComovingTransverseDistanceZ1Z2(0, z)
@@ -61,6 +171,22 @@ func (cos WACDM) ComovingTransverseDistanceZ1Z2(z1, z2 float64) (distanceMpcRad
 	return comovingTransverseDistanceZ1Z2(cos, z1, z2)
 }
 
+// ComovingVolume is the comoving volume of the whole sky out to z.
+func (cos WACDM) ComovingVolume(z float64) (volumeMpc3 float64) {
+	return comovingVolume(cos, z)
+}
+
+// ComovingVolumeZ1Z2 is the comoving volume of the whole sky between z1 and z2.
+func (cos WACDM) ComovingVolumeZ1Z2(z1, z2 float64) (volumeMpc3 float64) {
+	return cos.ComovingVolume(z2) - cos.ComovingVolume(z1)
+}
+
+// DifferentialComovingVolume is the comoving volume per unit redshift
+// per steradian at z.
+func (cos WACDM) DifferentialComovingVolume(z float64) (volumeMpc3Sr float64) {
+	return differentialComovingVolume(cos, z)
+}
+
 // HubbleDistance is the inverse of the Hubble parameter
 //   distance : [Mpc]
 func (cos WACDM) HubbleDistance() float64 {
@@ -79,10 +205,9 @@ func (cos WACDM) ComovingDistance(z float64) (distanceMpc float64) {
 }
 
 // ComovingDistanceZ1Z2Integrate is the comoving distance between two z
-// in a flat lambda CDM cosmology using fixed Gaussian quadrature integration.
+// in a flat lambda CDM cosmology using the numerical integration of cos.Integration.
 func (cos WACDM) comovingDistanceZ1Z2Integrate(z1, z2 float64) (distanceMpc float64) {
-	n := 1000 // Integration will be n-point Gaussian quadrature
-	return cos.HubbleDistance() * quad.Fixed(cos.Einv, z1, z2, n, nil, 0)
+	return cos.HubbleDistance() * cos.Integration.integrate(cos.Einv, z1, z2)
 }
 
 // ComovingDistanceZ1Z2 is the base function for calculation of comoving distances
@@ -93,10 +218,10 @@ func (cos WACDM) ComovingDistanceZ1Z2(z1, z2 float64) (distanceMpc float64) {
 	// Test for Ol0==0 first so that (Om0, Ol0) = (1, 0)
 	// is handled by the analytic solution
 	// rather than the explicit integration.
-	case cos.Ol0 == 0:
+	case (cos.Ol0 == 0) && (0 < cos.Om0) && (cos.Om0 <= 1) && (cos.Tcmb0 == 0):
 		return comovingDistanceOMZ1Z2(z1, z2, cos.Om0, cos.H0)
 	case cos.WA == 0:
-		wcdm_cos := WCDM{H0: cos.H0, Om0: cos.Om0, Ol0: cos.Ol0, W0: cos.W0}
+		wcdm_cos := WCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Ol0: cos.Ol0, W0: cos.W0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
 		return wcdm_cos.ComovingDistanceZ1Z2(z1, z2)
 	default:
 		return cos.comovingDistanceZ1Z2Integrate(z1, z2)
@@ -106,10 +231,10 @@ func (cos WACDM) ComovingDistanceZ1Z2(z1, z2 float64) (distanceMpc float64) {
 // LookbackTime is the time from redshift 0 to z in Gyr.
 func (cos WACDM) LookbackTime(z float64) (timeGyr float64) {
 	switch {
-	case (cos.Ol0 == 0) && (0 < cos.Om0) && (cos.Om0 != 1):
+	case (cos.Ol0 == 0) && (0 < cos.Om0) && (cos.Om0 < 1) && (cos.Tcmb0 == 0):
 		return lookbackTimeOM(z, cos.Om0, cos.H0)
 	case cos.WA == 0:
-		wcdm_cos := WCDM{H0: cos.H0, Om0: cos.Om0, Ol0: cos.Ol0, W0: cos.W0}
+		wcdm_cos := WCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Ol0: cos.Ol0, W0: cos.W0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
 		return wcdm_cos.LookbackTime(z)
 	default:
 		return cos.lookbackTimeIntegrate(z)
@@ -118,57 +243,72 @@ func (cos WACDM) LookbackTime(z float64) (timeGyr float64) {
 
 // lookbackTimeIntegrate is the lookback time using explicit integration
 func (cos WACDM) lookbackTimeIntegrate(z float64) (timeGyr float64) {
-	n := 1000 // Integration will be n-point Gaussian quadrature
 	integrand := func(z float64) float64 { return cos.Einv(z) / (1 + z) }
-	return hubbleTime(cos.H0) * quad.Fixed(integrand, 0, z, n, nil, 0)
+	return hubbleTime(cos.H0) * cos.Integration.integrate(integrand, 0, z)
 }
 
 // Age is the time from redshift ∞ to z in Gyr.
 func (cos WACDM) Age(z float64) (timeGyr float64) {
 	switch {
-	case (cos.Ol0 == 0) && (0 < cos.Om0) && (cos.Om0 != 1):
+	case (cos.Ol0 == 0) && (0 < cos.Om0) && (cos.Om0 < 1) && (cos.Tcmb0 == 0):
 		return ageOM(z, cos.Om0, cos.H0)
 	case cos.WA == 0:
-		wcdm_cos := WCDM{H0: cos.H0, Om0: cos.Om0, Ol0: cos.Ol0, W0: cos.W0}
+		wcdm_cos := WCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Ol0: cos.Ol0, W0: cos.W0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
 		return wcdm_cos.Age(z)
 	default:
 		return cos.ageIntegrate(z)
 	}
 }
 
+// zAtAge is the inverse of Age, for the cases in which Age is analytic.
+// ok is false for the other cases.
+func (cos WACDM) zAtAge(timeGyr float64) (z float64, ok bool) {
+	switch {
+	case (cos.Ol0 == 0) && (0 < cos.Om0) && (cos.Om0 < 1) && (cos.Tcmb0 == 0):
+		return math.NaN(), false
+	case cos.WA == 0:
+		wcdm_cos := WCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Ol0: cos.Ol0, W0: cos.W0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
+		return wcdm_cos.zAtAge(timeGyr)
+	default:
+		return math.NaN(), false
+	}
+}
+
 // ageIntegrate is the time from redshift ∞ to z
 // using explicit integration.
 //
 // Basic integrand can be found in many texts
 // I happened to copy this from
 // Thomas and Kantowski, 2000, PRD, 62, 103507.  Eq. 1.
-// Current implementation is fixed quadrature using mathext.integrate.quad.Fixed
+// The numerical integration is set by cos.Integration.
 func (cos WACDM) ageIntegrate(z float64) (timeGyr float64) {
-	n := 1000 // Integration will be n-point Gaussian quadrature
 	integrand := func(z float64) float64 {
 		denom := (1 + z) * cos.E(z)
 		return 1 / denom
 	}
-	// When given math.Inf(), quad.Fixed automatically redefines variables
-	// to successfully do the numerical integration.
-	return hubbleTime(cos.H0) * quad.Fixed(integrand, z, math.Inf(1), n, nil, 0)
+	return hubbleTime(cos.H0) * cos.Integration.integrate(integrand, z, math.Inf(1))
 }
 
-// E is the Hubble parameter as a fraction of its present value.
-// E.g., Hogg arXiv:9905116  Eq. 14
-// Linder, 2003, PhRvL, 90, 130, Eq. 5, 7
-func (cos WACDM) E(z float64) (fractionalHubbleParameter float64) {
-	oR := cos.Ogamma0 + cos.Onu0
-	var deScale float64
+// deScale is the dark energy density at z relative to z=0,
+//   (1+z)^(3(1+w0+wa)) exp(-3 wa z/(1+z))
+//   Linder, 2003, PhRvL, 90, 130, Eq. 5, 7
+func (cos WACDM) deScale(z float64) (densityScale float64) {
 	switch {
 	case (cos.W0 == -1) && (cos.WA == 0):
-		deScale = 1
+		return 1
 	case cos.WA == 0:
-		deScale = math.Pow(1+z, 3*(1+cos.W0))
-	default:
-		deScale = math.Pow(1+z, 3*(1+cos.W0+cos.WA)) * math.Exp(-3*cos.WA*z/(1+z))
+		return math.Pow(1+z, 3*(1+cos.W0))
 	}
-	Ok0 := 1 - (cos.Om0 + cos.Ol0)
+	return math.Pow(1+z, 3*(1+cos.W0+cos.WA)) * math.Exp(-3*cos.WA*z/(1+z))
+}
+
+// E is the Hubble parameter as a fraction of its present value.
+// E.g., Hogg arXiv:9905116  Eq. 14
+// Linder, 2003, PhRvL, 90, 130, Eq. 5, 7
+func (cos WACDM) E(z float64) (fractionalHubbleParameter float64) {
+	oR := cos.Ogamma0() * (1 + nuRelativeDensity(z, cos.Tcmb0, cos.Neff, cos.MNu))
+	deScale := cos.deScale(z)
+	Ok0 := cos.Ok0()
 	return math.Sqrt((1+z)*(1+z)*(1+z)*(1+z)*oR + (1+z)*(1+z)*(1+z)*cos.Om0 +
 		(1+z)*(1+z)*Ok0 + cos.Ol0*deScale)
 }
@@ -0,0 +1,439 @@
+package cosmo
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/integrate/quad"
+	"math"
+)
+
+// WzCDM provides cosmological distances, age, and look-back time
+// for a cosmology with a general dark energy equation of state:
+// matter, dark energy, and curvature,
+// with a user-supplied w(z) equation-of-state parameter for dark energy.
+//
+// The evolution of the dark energy density is
+//   rho_DE(z)/rho_DE(0) = exp(3 * Integral_0^z (1+w(z'))/(1+z') dz')
+// which a WzCDM made by NewWzCDM tabulates once in ln(1+z) and interpolates.
+// A WzCDM struct literal integrates it numerically for every call to E(z),
+// which is ~ 20x slower.
+// If the density scaling is known in closed form it can be supplied
+// directly as DEScale, which then takes precedence over W.
+// If neither is given, the dark energy is a cosmological constant.
+type WzCDM struct {
+	H0          float64                 // Hubble constant at z=0.  [km/s/Mpc]
+	Om0         float64                 // Matter Density at z=0
+	Ob0         float64                 // Baryon density at z=0, included in Om0
+	Ol0         float64                 // Dark Energy density at z=0
+	W           func(z float64) float64 // Dark energy equation-of-state parameter, w(z) = p/rho
+	DEScale     func(z float64) float64 // Dark energy density relative to z=0, rho_DE(z)/rho_DE(0)
+	Tcmb0       float64                 // Temperature of the CMB at z=0.  [K]
+	Neff        float64                 // Effective number of neutrino species
+	MNu         []float64               // Masses of the neutrino species.  [eV]
+	Integration Integration             // Numerical integration of distances and times
+	lnDEScale   *cubicHermite           // ln(DEScale) tabulated from W by NewWzCDM
+}
+
+func (cos WzCDM) String() string {
+	return fmt.Sprintf("WzCDM{H0: %v, Om0: %v, Ol0: %v}",
+		cos.H0, cos.Om0, cos.Ol0)
+}
+
+// NewWzCDM creates a WzCDM and validates its parameters.
+// It tabulates the dark energy density from W once,
+// so W must not be changed on the result.
+// The radiation, if any, is given as an optional Radiation
+// and validated with the other parameters.
+func NewWzCDM(H0, Om0, Ol0 float64, W func(z float64) float64, rad ...Radiation) (WzCDM, error) {
+	r, err := oneRadiation("WzCDM", rad)
+	if err != nil {
+		return WzCDM{}, err
+	}
+	cos := WzCDM{H0: H0, Om0: Om0, Ol0: Ol0, W: W, Tcmb0: r.Tcmb0, Neff: r.Neff, MNu: r.MNu}
+	if err := cos.Validate(); err != nil {
+		return WzCDM{}, err
+	}
+	if W != nil {
+		cos.lnDEScale = newLnDEScaleTable(W)
+	}
+	return cos, nil
+}
+
+// Ok0 is the curvature density at z=0
+func (cos WzCDM) Ok0() (curvatureDensity float64) {
+	return 1 - (cos.Om0 + cos.Ol0 + cos.Ogamma0() + cos.Onu0())
+}
+
+// integration is the numerical integration of distances and times
+func (cos WzCDM) integration() Integration {
+	return cos.Integration
+}
+
+// Ogamma0 is the photon density at z=0
+func (cos WzCDM) Ogamma0() (photonDensity float64) {
+	return ogamma0(cos.H0, cos.Tcmb0)
+}
+
+// Onu0 is the neutrino density at z=0
+func (cos WzCDM) Onu0() (neutrinoDensity float64) {
+	return onu0(cos.H0, cos.Tcmb0, cos.Neff, cos.MNu)
+}
+
+// Om is the matter density at z as a fraction of the critical density at z
+func (cos WzCDM) Om(z float64) (matterDensity float64) {
+	return cos.Om0 * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Ob is the baryon density at z as a fraction of the critical density at z
+func (cos WzCDM) Ob(z float64) (baryonDensity float64) {
+	return cos.Ob0 * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Tcmb is the temperature of the CMB at z.  [K]
+func (cos WzCDM) Tcmb(z float64) (temperatureK float64) {
+	return cos.Tcmb0 * (1 + z)
+}
+
+// Ode is the dark energy density at z as a fraction of the critical density at z
+func (cos WzCDM) Ode(z float64) (darkEnergyDensity float64) {
+	return cos.Ol0 * cos.deScale(z) / (cos.E(z) * cos.E(z))
+}
+
+// Ok is the curvature density at z as a fraction of the critical density at z
+func (cos WzCDM) Ok(z float64) (curvatureDensity float64) {
+	return cos.Ok0() * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Ogamma is the photon density at z as a fraction of the critical density at z
+func (cos WzCDM) Ogamma(z float64) (photonDensity float64) {
+	return cos.Ogamma0() * (1 + z) * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// Onu is the neutrino density at z as a fraction of the critical density at z
+func (cos WzCDM) Onu(z float64) (neutrinoDensity float64) {
+	return cos.Ogamma0() * nuRelativeDensity(z, cos.Tcmb0, cos.Neff, cos.MNu) *
+		(1 + z) * (1 + z) * (1 + z) * (1 + z) / (cos.E(z) * cos.E(z))
+}
+
+// H is the Hubble parameter at z.  [km/s/Mpc]
+func (cos WzCDM) H(z float64) (hubbleParameterKmSMpc float64) {
+	return cos.H0 * cos.E(z)
+}
+
+// CriticalDensity is the critical density at z.  [g/cm^3]
+func (cos WzCDM) CriticalDensity(z float64) (densityGCm3 float64) {
+	return criticalDensity0(cos.H0) * cos.E(z) * cos.E(z)
+}
+
+// CriticalDensityMsunMpc3 is the critical density at z.  [Msun/Mpc^3]
+func (cos WzCDM) CriticalDensityMsunMpc3(z float64) (densityMsunMpc3 float64) {
+	return cos.CriticalDensity(z) * gCm3ToMsunMpc3
+}
+
+// lambdaMatter returns Om0 and Ok0 if the cosmology has only
+// matter, curvature, and a cosmological constant, for which
+// the linear growth factor is given by the Heath integral.
+func (cos WzCDM) lambdaMatter() (Om0, Ok0 float64, ok bool) {
+	if !cos.isLambda() {
+		return 0, 0, false
+	}
+	return cos.lambdaCDM().lambdaMatter()
+}
+
+// Validate checks that the parameters describe a physical cosmology.
+// The error, if any, is a *ParameterError for the first problem found.
+func (cos WzCDM) Validate() error {
+	const name = "WzCDM"
+	return firstError(
+		validateH0(name, cos.H0),
+		validateMatter(name, cos.Om0, cos.Ob0),
+		validateFinite(name, []string{"Ol0"}, cos.Ol0),
+		validateRadiation(name, cos.Tcmb0, cos.Neff, cos.MNu),
+		cos.Integration.validate(name),
+		cos.validateDEScale(),
+	)
+}
+
+// validateDEScale checks that a supplied DEScale is normalized to 1 at z=0.
+func (cos WzCDM) validateDEScale() error {
+	if cos.DEScale == nil {
+		return nil
+	}
+	if s := cos.DEScale(0); !(math.Abs(s-1) <= 1e-9) {
+		return &ParameterError{Cosmology: "WzCDM", Parameter: "DEScale(0)", Value: s, Err: ErrInconsistent,
+			Detail: "dark energy density scaling must be 1 at z=0"}
+	}
+	return nil
+}
+
+// DistanceModulus is the magnitude difference between 1 Mpc and
+// the luminosity distance for the given z.
+func (cos WzCDM) DistanceModulus(z float64) (distanceModulusMag float64) {
+	return 5*math.Log10(cos.LuminosityDistance(z)) + 25
+}
+
+// LuminosityDistance is the radius of effective sphere over which the light has spread out
+func (cos WzCDM) LuminosityDistance(z float64) (distanceMpc float64) {
+	return (1 + z) * cos.ComovingTransverseDistance(z)
+}
+
+// AngularDiameterDistance is the ratio of physical transverse size to angular size
+func (cos WzCDM) AngularDiameterDistance(z float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistance(z) / (1 + z)
+}
+
+// AngularDiameterDistanceZ1Z2 is the angular diameter distance of an object at z2
+// as seen by an observer at z1, e.g., D_ls for a lens at z1 and a source at z2.
+func (cos WzCDM) AngularDiameterDistanceZ1Z2(z1, z2 float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistanceZ1Z2(z1, z2) / (1 + z2)
+}
+
+// ComovingTransverseDistance is the comoving distance at z as seen from z=0
+func (cos WzCDM) ComovingTransverseDistance(z float64) (distanceMpcRad float64) {
+	return cos.ComovingTransverseDistanceZ1Z2(0, z)
+}
+
+// ComovingTransverseDistanceZ1Z2 is the comoving distance at z2 as seen from z1
+func (cos WzCDM) ComovingTransverseDistanceZ1Z2(z1, z2 float64) (distanceMpcRad float64) {
+	return comovingTransverseDistanceZ1Z2(cos, z1, z2)
+}
+
+// ComovingVolume is the comoving volume of the whole sky out to z.
+func (cos WzCDM) ComovingVolume(z float64) (volumeMpc3 float64) {
+	return comovingVolume(cos, z)
+}
+
+// ComovingVolumeZ1Z2 is the comoving volume of the whole sky between z1 and z2.
+func (cos WzCDM) ComovingVolumeZ1Z2(z1, z2 float64) (volumeMpc3 float64) {
+	return cos.ComovingVolume(z2) - cos.ComovingVolume(z1)
+}
+
+// DifferentialComovingVolume is the comoving volume per unit redshift
+// per steradian at z.
+func (cos WzCDM) DifferentialComovingVolume(z float64) (volumeMpc3Sr float64) {
+	return differentialComovingVolume(cos, z)
+}
+
+// HubbleDistance is the inverse of the Hubble parameter
+//   distance : [Mpc]
+func (cos WzCDM) HubbleDistance() float64 {
+	return hubbleDistance(cos.H0)
+}
+
+// ComovingDistance is the distance that is constant with the Hubble flow
+// expressed in the physical distance at z=0.
+//
+// As the scale factor a = 1/(1+z) increases from 0.5 to 1,
+// two objects separated by a proper distance of 10 Mpc at a=0.5 (z=1)
+// will be separated by a proper distance of 2*10 Mpc at a=1.0 (z=0).
+// The comoving distance between these objects is 20 Mpc.
+func (cos WzCDM) ComovingDistance(z float64) (distanceMpc float64) {
+	return cos.ComovingDistanceZ1Z2(0, z)
+}
+
+// ComovingDistanceZ1Z2Integrate is the comoving distance between two z
+// in a general dark energy cosmology using the numerical integration of cos.Integration.
+func (cos WzCDM) comovingDistanceZ1Z2Integrate(z1, z2 float64) (distanceMpc float64) {
+	return cos.HubbleDistance() * cos.Integration.integrate(cos.Einv, z1, z2)
+}
+
+// ComovingDistanceZ1Z2 is the base function for calculation of comoving distances
+// Here is where the choice of fundamental calculation method is made:
+// Fall back to simpler cosmology, or quadature integration
+func (cos WzCDM) ComovingDistanceZ1Z2(z1, z2 float64) (distanceMpc float64) {
+	switch {
+	// Test for Ol0==0 first so that (Om0, Ol0) = (1, 0)
+	// is handled by the analytic solution
+	// rather than the explicit integration.
+	case (cos.Ol0 == 0) && (0 < cos.Om0) && (cos.Om0 <= 1) && (cos.Tcmb0 == 0):
+		return comovingDistanceOMZ1Z2(z1, z2, cos.Om0, cos.H0)
+	case cos.isLambda():
+		return cos.lambdaCDM().ComovingDistanceZ1Z2(z1, z2)
+	default:
+		return cos.comovingDistanceZ1Z2Integrate(z1, z2)
+	}
+}
+
+// LookbackTime is the time from redshift 0 to z.
+func (cos WzCDM) LookbackTime(z float64) (timeGyr float64) {
+	switch {
+	case (cos.Ol0 == 0) && (0 < cos.Om0) && (cos.Om0 < 1) && (cos.Tcmb0 == 0):
+		return lookbackTimeOM(z, cos.Om0, cos.H0)
+	case cos.isLambda():
+		return cos.lambdaCDM().LookbackTime(z)
+	default:
+		return cos.lookbackTimeIntegrate(z)
+	}
+}
+
+// lookbackTimeIntegrate is the lookback time using explicit integration
+func (cos WzCDM) lookbackTimeIntegrate(z float64) (timeGyr float64) {
+	integrand := func(z float64) float64 { return cos.Einv(z) / (1 + z) }
+	return hubbleTime(cos.H0) * cos.Integration.integrate(integrand, 0, z)
+}
+
+// Age is the time from redshift ∞ to z.
+func (cos WzCDM) Age(z float64) (timeGyr float64) {
+	switch {
+	case (cos.Ol0 == 0) && (0 < cos.Om0) && (cos.Om0 < 1) && (cos.Tcmb0 == 0):
+		return ageOM(z, cos.Om0, cos.H0)
+	case cos.isLambda():
+		return cos.lambdaCDM().Age(z)
+	default:
+		return cos.ageIntegrate(z)
+	}
+}
+
+// zAtAge is the inverse of Age, for the cases in which Age is analytic.
+// ok is false for the other cases.
+func (cos WzCDM) zAtAge(timeGyr float64) (z float64, ok bool) {
+	switch {
+	case (cos.Ol0 == 0) && (0 < cos.Om0) && (cos.Om0 < 1) && (cos.Tcmb0 == 0):
+		return math.NaN(), false
+	case cos.isLambda():
+		return cos.lambdaCDM().zAtAge(timeGyr)
+	default:
+		return math.NaN(), false
+	}
+}
+
+// ageIntegrate is the time from redshift ∞ to z
+// using explicit integration.
+//
+// Basic integrand can be found in many texts
+// I happened to copy this from
+// Thomas and Kantowski, 2000, PRD, 62, 103507.  Eq. 1.
+// The numerical integration is set by cos.Integration.
+func (cos WzCDM) ageIntegrate(z float64) (timeGyr float64) {
+	integrand := func(z float64) float64 {
+		denom := (1 + z) * cos.E(z)
+		return 1 / denom
+	}
+	return hubbleTime(cos.H0) * cos.Integration.integrate(integrand, z, math.Inf(1))
+}
+
+// isLambda is true if neither W nor DEScale is given,
+// i.e., if the dark energy is a cosmological constant.
+func (cos WzCDM) isLambda() bool {
+	return (cos.W == nil) && (cos.DEScale == nil)
+}
+
+// lambdaCDM is the equivalent LambdaCDM cosmology
+// for the case of a cosmological constant.
+func (cos WzCDM) lambdaCDM() LambdaCDM {
+	return LambdaCDM{H0: cos.H0, Om0: cos.Om0, Ob0: cos.Ob0, Ol0: cos.Ol0, Tcmb0: cos.Tcmb0, Neff: cos.Neff, MNu: cos.MNu, Integration: cos.Integration}
+}
+
+// deScale is the dark energy density at z relative to z=0.
+//
+// If DEScale is not given, it is calculated from W as
+//   exp(3 * Integral_0^ln(1+z) (1+w) dln(1+z))
+// The integral is done in ln(1+z) so that the same fixed quadrature
+// remains well behaved out to the very large z reached by ageIntegrate.
+// Within the table of NewWzCDM it is interpolated instead,
+// and beyond the table integrated from the end of the table.
+func (cos WzCDM) deScale(z float64) (densityScale float64) {
+	switch {
+	case cos.DEScale != nil:
+		return cos.DEScale(z)
+	case cos.W == nil:
+		return 1
+	}
+	x := math.Log1p(z)
+	if cos.lnDEScale != nil {
+		switch {
+		case (lnDEScaleMin <= x) && (x < lnDEScaleMax):
+			// The table is uniform in ln(1+z).
+			i := int((x - lnDEScaleMin) / lnDEScaleStep)
+			if i > len(cos.lnDEScale.x)-2 {
+				i = len(cos.lnDEScale.x) - 2
+			}
+			return math.Exp(cos.lnDEScale.atSegment(i, x))
+		case x >= lnDEScaleMax:
+			return math.Exp(cos.lnDEScale.at(lnDEScaleMax) + lnDEScaleIntegral(cos.W, lnDEScaleMax, x))
+		}
+	}
+	return math.Exp(lnDEScaleIntegral(cos.W, 0, x))
+}
+
+// lnDEScaleIntegral is
+//   3 * Integral_x1^x2 (1+w) dln(1+z)
+// with 100-point Gauss-Legendre quadrature.
+func lnDEScaleIntegral(w func(float64) float64, x1, x2 float64) float64 {
+	n := 100 // Integration will be n-point Gaussian quadrature
+	integrand := func(lnopz float64) float64 { return 1 + w(math.Expm1(lnopz)) }
+	// quad.Fixed needs its limits in order, so integrate back for future redshifts.
+	if x2 < x1 {
+		return -3 * quad.Fixed(integrand, x2, x1, n, nil, 0)
+	}
+	return 3 * quad.Fixed(integrand, x1, x2, n, nil, 0)
+}
+
+// The range and spacing in ln(1+z) of the table of ln(DEScale),
+// from z ~ -0.993 to z ~ 5e8, beyond the start of the growth equation at growthAInit.
+const (
+	lnDEScaleMin  = -5
+	lnDEScaleMax  = 20
+	lnDEScaleStep = 0.01
+)
+
+// newLnDEScaleTable tabulates
+//   ln(DEScale) = 3 * Integral_0^ln(1+z) (1+w) dln(1+z)
+// cumulatively outwards from z=0.
+// Its derivative 3 (1+w) is exact, so the cubic Hermite interpolation
+// is accurate to O(lnDEScaleStep^4).
+func newLnDEScaleTable(w func(float64) float64) *cubicHermite {
+	integrand := func(lnopz float64) float64 { return 3 * (1 + w(math.Expm1(lnopz))) }
+	integrate := newCellIntegrator(integrand)
+	n := int(math.Round((lnDEScaleMax-lnDEScaleMin)/lnDEScaleStep)) + 1
+	i0 := int(math.Round(-lnDEScaleMin / lnDEScaleStep))
+	x := make([]float64, n)
+	y := make([]float64, n)
+	dydx := make([]float64, n)
+	for i := range x {
+		x[i] = lnDEScaleMin + float64(i)*lnDEScaleStep
+		dydx[i] = integrand(x[i])
+	}
+	x[i0] = 0
+	for i := i0 + 1; i < n; i++ {
+		y[i] = y[i-1] + integrate(x[i-1], x[i])
+	}
+	for i := i0 - 1; i >= 0; i-- {
+		y[i] = y[i+1] + integrate(x[i+1], x[i])
+	}
+	table := newCubicHermite(x, y, dydx)
+	return &table
+}
+
+// analyzeExpansion finds the turning points from the roots of E^2(z)
+// for a cosmological constant without radiation.
+func (cos WzCDM) analyzeExpansion() (e Expansion, ok bool) {
+	if !cos.isLambda() {
+		return Expansion{}, false
+	}
+	return cos.lambdaCDM().analyzeExpansion()
+}
+
+// E is the Hubble parameter as a fraction of its present value.
+// E.g., Hogg arXiv:9905116  Eq. 14
+func (cos WzCDM) E(z float64) (fractionalHubbleParameter float64) {
+	oR := cos.Ogamma0() * (1 + nuRelativeDensity(z, cos.Tcmb0, cos.Neff, cos.MNu))
+	deScale := cos.deScale(z)
+	Ok0 := cos.Ok0()
+	return math.Sqrt((1+z)*(1+z)*(1+z)*(1+z)*oR + (1+z)*(1+z)*(1+z)*cos.Om0 +
+		(1+z)*(1+z)*Ok0 + cos.Ol0*deScale)
+}
+
+// Einv is the inverse Hubble parameter
+// Implementation is just to return E(z)
+func (cos WzCDM) Einv(z float64) (invFractionalHubbleParameter float64) {
+	// 1/Sqrt() is not notably slower than Pow(-0.5)
+	//
+	// Pow(-0.5) is in fact implemented as 1/Sqrt() in math.pow.go
+	// func pow(x, y float64) float64 {
+	//    [...]
+	// case y == -0.5:
+	//    return 1 / Sqrt(x)
+	//
+	// Thus we just return the inverse of E(z) instead of rewriting out here.
+	return 1 / cos.E(z)
+}
@@ -0,0 +1,132 @@
+package cosmo
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+var zWzCDM = []float64{0.5, 1.0, 2.0, 3.0}
+
+// wConst returns a constant equation of state w(z) = w0
+func wConst(w0 float64) func(float64) float64 {
+	return func(z float64) float64 { return w0 }
+}
+
+// wLinder returns the Linder 2003 equation of state
+//   w(z) = w0 + wa * (1-a) = w0 + wa * z/(1+z)
+func wLinder(w0, wa float64) func(float64) float64 {
+	return func(z float64) float64 { return w0 + wa*z/(1+z) }
+}
+
+// Calculated via Python AstroPy
+//   from astropy.cosmology import wCDM, w0waCDM
+//   z = np.asarray([0.5, 1.0, 2.0, 3.0])
+// for the equivalent w(z) parameterization.
+var testTableWzCDM = map[string]struct {
+	cos      WzCDM
+	function string
+	exp      []float64
+}{
+	//   wCDM(70, 0.3, 0.7, -1.2).distmod(z)
+	"WzCDMDistanceModulus": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: wConst(-1.2)}, "DistanceModulus", []float64{42.32710911, 44.17957201, 46.03118144, 47.09228735}},
+	//   wCDM(70, 0.3, 0.6, -0.8).luminosity_distance(z)
+	"WzCDMLuminosityDistanceNonflat": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.6, W: wConst(-0.8)}, "LuminosityDistance", []float64{2713.4660301, 6257.24866642, 14794.59911147, 24496.30592953}},
+	//   LambdaCDM(70, 0.3, 0.9).luminosity_distance(z)
+	"WzCDMLuminosityDistanceNegativeOkLCDM": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.9}, "LuminosityDistance", []float64{2933.96568944, 6896.93040403, 15899.60122012, 25287.53295915}},
+	//   w0waCDM(70, 0.3, 0.6, -0.9, 2).luminosity_distance(z)
+	"WzCDMLuminosityDistanceNonflatWA": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.6, W: wLinder(-0.9, 2)}, "LuminosityDistance", []float64{2659.67537448, 5901.12663329, 13049.93089016, 20468.18548013}},
+	//   w0waCDM(70, 0.3, 0.7, -0.8, 2.5).angular_diameter_distance(z)
+	"WzCDMAngularDiameterDistance": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: wLinder(-0.8, 2.5)}, "AngularDiameterDistance", []float64{1155.52181127, 1393.61319898, 1282.08090454, 1073.63096224}},
+	//   w0waCDM(70, 0.3, 0.7, -1.2, -1.2).comoving_transverse_distance(z)
+	"WzCDMComovingTransverseDistance": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: wLinder(-1.2, -1.2)}, "ComovingTransverseDistance", []float64{1985.54631561, 3533.91345688, 5524.66720808, 6731.56420461}},
+	//   w0waCDM(70, 0.3, 0.7, -0.9, 3.5)._comoving_distance_z1z2(0, z)
+	"WzCDMComovingDistanceZ1Z2Integrate": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: wLinder(-0.9, 3.5)}, "ComovingDistanceZ1Z2", []float64{1726.71519955, 2709.17698433, 3538.63486291, 3798.28908226}},
+	//   wCDM(70, 0.3, 0., -0.9).comoving_distance(z)
+	"WzCDMComovingDistanceNonflatOM": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0., W: wConst(-0.9)}, "ComovingDistance", []float64{1679.81156606, 2795.15602075, 4244.25192263, 5178.38877021}},
+	//   wCDM(70, 0.3, 0.7, -1.1).lookback_time(z)
+	"WzCDMLookbackTimeIntegrate": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: wConst(-1.1)}, "LookbackTime", []float64{5.11509518, 7.85406053, 10.42213038, 11.54588106}},
+	//   w0waCDM(70, 0.3, 0.7, -1.1, 2.8).lookback_time(z)
+	"WzCDMLookbackTimeIntegrateWA": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: wLinder(-1.1, 2.8)}, "LookbackTime", []float64{4.86957219, 7.09750717, 8.81344126, 9.36817438}},
+	//   wCDM(70, 0, 0.5, -0.9).lookback_time(z)
+	"WzCDMLookbackTimeOL": {WzCDM{H0: 70, Om0: 0, Ol0: 0.5, W: wConst(-0.9)}, "LookbackTime", []float64{5.00576631, 7.78841245, 10.76147941, 12.31462586}},
+	//   w0waCDM(70, 0.3, 0.6, -0.6, 3.5).age(z)
+	"WzCDMAge": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.6, W: wLinder(-0.6, 3.5)}, "Age", []float64{2.70980463, 1.08619498, 0.21688951, 0.058307}},
+	//   LambdaCDM(70, 0.3, 0.6).age(z)
+	"WzCDMAgeLCDM": {WzCDM{H0: 70, Om0: 0.3, Ol0: 0.6}, "Age", []float64{8.11137578, 5.54558439, 3.13456008, 2.06445301}},
+}
+
+func TestTableWzCDM(t *testing.T) {
+	for _, test := range testTableWzCDM {
+		switch {
+		case strings.HasSuffix(test.function, "Z1Z2"):
+			runTestsZ0Z2ByName(test.cos, test.function, zWzCDM, test.exp, distTol, t)
+		default:
+			runTestsByName(test.cos, test.function, zWzCDM, test.exp, distTol, t)
+		}
+	}
+}
+
+func TestWzCDMCosmologyInterface(t *testing.T) {
+	ageDistance := func(cos FLRW) {
+		z := 0.5
+		age := cos.Age(z)
+		dc := cos.ComovingDistance(z)
+		_, _ = age, dc
+	}
+
+	cos := WzCDM{H0: 70, Om0: 0.27, Ol0: 0.73, W: wConst(-1)}
+	ageDistance(cos)
+}
+
+// TestWzCDMDEScale checks that supplying the dark energy density scaling
+// directly agrees with integrating the equivalent w(z).
+func TestWzCDMDEScale(t *testing.T) {
+	w0, wa := -0.9, 0.5
+	scale := func(z float64) float64 {
+		return math.Pow(1+z, 3*(1+w0+wa)) * math.Exp(-3*wa*z/(1+z))
+	}
+	fromW := WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: wLinder(w0, wa)}
+	fromScale := WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, DEScale: scale}
+	zVec := []float64{0.5, 1.0, 10.0, 1000.0}
+	for _, z := range zVec {
+		runTest(fromW.E, z, fromScale.E(z), eTol*fromScale.E(z), t, 0)
+	}
+	for _, z := range zWzCDM {
+		runTest(fromW.Age, z, fromScale.Age(z), ageTol, t, 0)
+	}
+}
+
+// The table of NewWzCDM matches the direct integration of W,
+// inside the table, beyond it, and for future redshifts.
+func TestNewWzCDMTable(t *testing.T) {
+	for _, w := range []func(float64) float64{wConst(-0.9), wLinder(-0.9, 0.5), wLinder(-1.2, -1.2)} {
+		direct := WzCDM{H0: 70, Om0: 0.3, Ol0: 0.7, W: w}
+		cos, err := NewWzCDM(70, 0.3, 0.7, w)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		for _, z := range []float64{-0.999, -0.5, 0, 0.013, 0.5, 1.0, 10.0, 1000.0, 1e5, 1e8} {
+			runTest(cos.E, z, direct.E(z), 1e-10*direct.E(z), t, 0)
+		}
+		for _, z := range zWzCDM {
+			runTest(cos.ComovingDistance, z, direct.ComovingDistance(z), distTol, t, 0)
+			runTest(cos.Age, z, direct.Age(z), ageTol, t, 0)
+		}
+	}
+	if _, err := NewWzCDM(-70, 0.3, 0.7, wConst(-0.9)); err == nil {
+		t.Errorf("Expected an error for H0 < 0")
+	}
+}
+
+// TestWzCDMMatchesWACDM checks against WACDM including radiation and massive neutrinos.
+func TestWzCDMMatchesWACDM(t *testing.T) {
+	mNu := []float64{0.06, 0, 0}
+	cos := WzCDM{H0: 70, Om0: 0.3, Ol0: 0.6, W: wLinder(-0.9, 0.5), Tcmb0: 2.725, Neff: 3.04, MNu: mNu}
+	exp := WACDM{H0: 70, Om0: 0.3, Ol0: 0.6, W0: -0.9, WA: 0.5, Tcmb0: 2.725, Neff: 3.04, MNu: mNu}
+	for _, z := range zWzCDM {
+		runTest(cos.ComovingTransverseDistance, z, exp.ComovingTransverseDistance(z), distTol, t, 0)
+		runTest(cos.LookbackTime, z, exp.LookbackTime(z), ageTol, t, 0)
+		runTest(cos.Age, z, exp.Age(z), ageTol, t, 0)
+	}
+}